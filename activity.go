@@ -0,0 +1,335 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// activityPageSize is the number of activity events requested per page.
+	activityPageSize = 100
+
+	// activityWeek is the span of the activity log returned for each
+	// page number, where page 0 is the current week.
+	activityWeek = 7 * 24 * time.Hour
+)
+
+// Activity object types.
+const (
+	ActivityItem    = "item"
+	ActivityNote    = "note"
+	ActivityProject = "project"
+)
+
+// Activity event types.
+const (
+	EventAdded       = "added"
+	EventUpdated     = "updated"
+	EventDeleted     = "deleted"
+	EventCompleted   = "completed"
+	EventUncompleted = "uncompleted"
+	EventArchived    = "archived"
+	EventUnarchived  = "unarchived"
+	EventShared      = "shared"
+	EventLeft        = "left"
+)
+
+// ActivityParameters filter the activity log. Empty fields do not filter.
+// See https://developer.todoist.com/sync/v9/#get-activity-logs
+type ActivityParameters struct {
+	// ObjectType is one of ActivityItem, ActivityNote, or ActivityProject.
+	ObjectType string
+	ObjectID   string
+
+	// EventType is one of the Event constants, e.g., EventCompleted.
+	EventType string
+
+	ParentProjectID string
+	ParentItemID    string
+	InitiatorID     string
+
+	// Since and Until limit events to those on or after Since and
+	// before Until. The log is read a week at a time, back to the week
+	// containing Since. If Since is zero, only the current week is read,
+	// as for the API.
+	Since time.Time
+	Until time.Time
+}
+
+// form returns the parameters as form values.
+func (p ActivityParameters) form() url.Values {
+	form := url.Values{}
+
+	set := func(key, value string) {
+		if value != "" {
+			form.Set(key, value)
+		}
+	}
+	set("object_type", p.ObjectType)
+	set("object_id", p.ObjectID)
+	set("event_type", p.EventType)
+	set("parent_project_id", p.ParentProjectID)
+	set("parent_item_id", p.ParentItemID)
+	set("initiator_id", p.InitiatorID)
+
+	return form
+}
+
+// pages returns the first and last page numbers that cover the date range
+// at now. A week of margin is allowed at each end, as pages follow the
+// weeks of the user rather than a fixed offset from now.
+func (p ActivityParameters) pages(now time.Time) (first int, last int) {
+	if !p.Until.IsZero() && p.Until.Before(now) {
+		first = int(now.Sub(p.Until)/activityWeek) - 1
+		if first < 0 {
+			first = 0
+		}
+	}
+
+	if !p.Since.IsZero() && p.Since.Before(now) {
+		last = int(now.Sub(p.Since)/activityWeek) + 1
+	}
+	if last < first {
+		last = first
+	}
+
+	return first, last
+}
+
+// ActivityExtraData holds details of an activity event. The Last fields
+// are the values before an update and are nil if the value did not change.
+type ActivityExtraData struct {
+	Content            string  `json:"content,omitempty"`
+	LastContent        *string `json:"last_content,omitempty"`
+	Description        string  `json:"description,omitempty"`
+	LastDescription    *string `json:"last_description,omitempty"`
+	DueDate            string  `json:"due_date,omitempty"`
+	LastDueDate        *string `json:"last_due_date,omitempty"`
+	ResponsibleUID     string  `json:"responsible_uid,omitempty"`
+	LastResponsibleUID *string `json:"last_responsible_uid,omitempty"`
+	Name               string  `json:"name,omitempty"`
+	LastName           *string `json:"last_name,omitempty"`
+
+	Client             string `json:"client,omitempty"`
+	NoteCount          int    `json:"note_count,omitempty"`
+	ParentProjectName  string `json:"parent_project_name,omitempty"`
+	ParentProjectColor string `json:"parent_project_color,omitempty"`
+	ParentItemContent  string `json:"parent_item_content,omitempty"`
+}
+
+// An ActivityChange is a field changed by an activity event.
+type ActivityChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// An ActivityEvent is an entry in the activity log.
+type ActivityEvent struct {
+	ID              string            `json:"id"`
+	ObjectType      string            `json:"object_type"`
+	ObjectID        string            `json:"object_id"`
+	EventType       string            `json:"event_type"`
+	EventDate       string            `json:"event_date"`
+	ParentProjectID string            `json:"parent_project_id,omitempty"`
+	ParentItemID    string            `json:"parent_item_id,omitempty"`
+	InitiatorID     string            `json:"initiator_id,omitempty"`
+	ExtraData       ActivityExtraData `json:"extra_data"`
+}
+
+// Time returns the time of the event.
+func (e ActivityEvent) Time() (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, e.EventDate)
+}
+
+// Changes returns the fields changed by the event with their values before
+// and after the event.
+func (e ActivityEvent) Changes() []ActivityChange {
+	d := e.ExtraData
+
+	fields := []struct {
+		name   string
+		before *string
+		after  string
+	}{
+		{"content", d.LastContent, d.Content},
+		{"description", d.LastDescription, d.Description},
+		{"due_date", d.LastDueDate, d.DueDate},
+		{"responsible_uid", d.LastResponsibleUID, d.ResponsibleUID},
+		{"name", d.LastName, d.Name},
+	}
+
+	var changes []ActivityChange
+	for _, f := range fields {
+		if f.before != nil && *f.before != f.after {
+			changes = append(changes,
+				ActivityChange{Field: f.name, Before: *f.before, After: f.after})
+		}
+	}
+
+	return changes
+}
+
+// An ActivityIterator iterates over activity events, requesting pages from
+// the API as needed.
+//
+//	it := client.Activity(ctx, ActivityParameters{EventType: EventCompleted})
+//	for it.Next() {
+//		event := it.Event()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ActivityIterator struct {
+	ctx      context.Context
+	client   *TodoistClient
+	params   ActivityParameters
+	page     []ActivityEvent
+	event    ActivityEvent
+	pageNum  int
+	lastPage int
+	offset   int
+	done     bool
+	err      error
+}
+
+// Activity returns an iterator over the activity events matching params,
+// newest first.
+// See https://developer.todoist.com/sync/v9/#get-activity-logs
+func (c *TodoistClient) Activity(ctx context.Context, params ActivityParameters) *ActivityIterator {
+	it := &ActivityIterator{ctx: ctx, client: c, params: params}
+	it.pageNum, it.lastPage = params.pages(time.Now())
+
+	return it
+}
+
+// Next advances to the next event, which is then available from Event.
+// Next returns false at the end or when an error occurs.
+func (it *ActivityIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.err != nil || it.done {
+			return false
+		}
+
+		it.err = it.fetch()
+	}
+
+	it.event, it.page = it.page[0], it.page[1:]
+
+	return true
+}
+
+// fetch requests the next set of events, moving to the previous week once
+// the events of the current week have been read.
+func (it *ActivityIterator) fetch() error {
+	form := it.params.form()
+	form.Set("page", strconv.Itoa(it.pageNum))
+	form.Set("limit", strconv.Itoa(activityPageSize))
+	form.Set("offset", strconv.Itoa(it.offset))
+
+	body, err := it.client.SyncPost(it.ctx, "/activity/get", form)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Events []ActivityEvent `json:"events"`
+		Count  int             `json:"count"`
+	}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return err
+	}
+
+	it.offset += len(response.Events)
+
+	it.page = make([]ActivityEvent, 0, len(response.Events))
+	for _, event := range response.Events {
+		t, err := event.Time()
+		if err != nil {
+			it.page = append(it.page, event)
+			continue
+		}
+
+		// events are newest first, so all later events are older
+		if !it.params.Since.IsZero() && t.Before(it.params.Since) {
+			it.done = true
+			return nil
+		}
+
+		if it.params.Until.IsZero() || t.Before(it.params.Until) {
+			it.page = append(it.page, event)
+		}
+	}
+
+	if len(response.Events) < activityPageSize || it.offset >= response.Count {
+		if it.pageNum >= it.lastPage {
+			it.done = true
+		}
+		it.pageNum++
+		it.offset = 0
+	}
+
+	return nil
+}
+
+// Event returns the current event.
+func (it *ActivityIterator) Event() ActivityEvent {
+	return it.event
+}
+
+// Err returns the first error that occurred, if any.
+func (it *ActivityIterator) Err() error {
+	return it.err
+}
+
+// GetActivity returns all activity events matching params.
+func (c *TodoistClient) GetActivity(ctx context.Context, params ActivityParameters) ([]ActivityEvent, error) {
+	var events []ActivityEvent
+
+	it := c.Activity(ctx, params)
+	for it.Next() {
+		events = append(events, it.Event())
+	}
+
+	return events, it.Err()
+}
+
+// WriteActivity writes the activity events matching params to w as JSON
+// Lines, one event per line, returning the number of events written.
+func (c *TodoistClient) WriteActivity(ctx context.Context, w io.Writer, params ActivityParameters) (int, error) {
+	enc := json.NewEncoder(w)
+
+	n := 0
+	it := c.Activity(ctx, params)
+	for it.Next() {
+		err := enc.Encode(it.Event())
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, it.Err()
+}
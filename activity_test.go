@@ -0,0 +1,234 @@
+package tdapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// activityServer serves total completed events, step apart and newest
+// first, starting now. Like the API, each page number returns the events
+// of one week, where page 0 is the current week, in sets of limit events
+// starting at offset.
+func activityServer(t *testing.T, total int, step time.Duration, forms *[]map[string]string) http.Handler {
+	now := time.Now().UTC()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sync/v9/activity/get" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+
+		r.ParseForm()
+		form := make(map[string]string)
+		for key := range r.Form {
+			form[key] = r.Form.Get(key)
+		}
+		*forms = append(*forms, form)
+
+		page, _ := strconv.Atoi(form["page"])
+		limit, _ := strconv.Atoi(form["limit"])
+		offset, _ := strconv.Atoi(form["offset"])
+
+		var week []ActivityEvent
+		for n := 0; n < total; n++ {
+			age := time.Duration(n) * step
+			if int(age/activityWeek) != page {
+				continue
+			}
+			week = append(week, ActivityEvent{
+				ID:         strconv.Itoa(n),
+				ObjectType: ActivityItem,
+				ObjectID:   fmt.Sprintf("item-%d", n),
+				EventType:  EventCompleted,
+				EventDate:  now.Add(-age).Format(time.RFC3339Nano),
+			})
+		}
+
+		events := []ActivityEvent{}
+		for n := offset; n < len(week) && n < offset+limit; n++ {
+			events = append(events, week[n])
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"events": events,
+			"count":  len(week),
+		})
+	})
+}
+
+func TestGetActivity(t *testing.T) {
+	var forms []map[string]string
+	api := newServerClient(t, activityServer(t, 250, time.Minute, &forms))
+
+	params := ActivityParameters{
+		ObjectType:      ActivityItem,
+		EventType:       EventCompleted,
+		ParentProjectID: "2203306141",
+		InitiatorID:     "1855589",
+	}
+	events, err := api.GetActivity(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(events) != 250 || events[249].ID != "249" {
+		t.Errorf("Got %d events, want 250", len(events))
+	}
+
+	var offsets []string
+	for _, form := range forms {
+		offsets = append(offsets, form["offset"])
+	}
+	if want := []string{"0", "100", "200"}; !reflect.DeepEqual(offsets, want) {
+		t.Errorf("offsets = %v, want %v", offsets, want)
+	}
+
+	want := map[string]string{
+		"object_type":       "item",
+		"event_type":        "completed",
+		"parent_project_id": "2203306141",
+		"initiator_id":      "1855589",
+		"page":              "0",
+		"limit":             "100",
+		"offset":            "0",
+	}
+	if !reflect.DeepEqual(forms[0], want) {
+		t.Errorf("form = %v, want %v", forms[0], want)
+	}
+}
+
+func TestGetActivityDateRange(t *testing.T) {
+	var forms []map[string]string
+
+	// events every 12 hours over five weeks
+	api := newServerClient(t, activityServer(t, 70, 12*time.Hour, &forms))
+
+	// from half way between events 40 and 41 to half way between 6 and 7
+	now := time.Now()
+	params := ActivityParameters{
+		Since: now.Add(-20*24*time.Hour - 6*time.Hour),
+		Until: now.Add(-3*24*time.Hour - 6*time.Hour),
+	}
+	events, err := api.GetActivity(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	var want []string
+	for n := 7; n <= 40; n++ {
+		want = append(want, strconv.Itoa(n))
+	}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("IDs = %v, want %v", ids, want)
+	}
+
+	// reading stops at the week containing Since
+	var pages []string
+	for _, form := range forms {
+		pages = append(pages, form["page"])
+		if _, ok := form["since"]; ok {
+			t.Errorf("Unexpected since in form %v", form)
+		}
+	}
+	if want := []string{"0", "1", "2"}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+}
+
+func TestGetActivityEmptyWeeks(t *testing.T) {
+	var forms []map[string]string
+
+	// two events three weeks apart, with empty weeks between them
+	api := newServerClient(t, activityServer(t, 2, 3*activityWeek, &forms))
+
+	params := ActivityParameters{Since: time.Now().Add(-4 * activityWeek)}
+	events, err := api.GetActivity(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("Got %d events, want 2", len(events))
+	}
+
+	// pages up to one week past the week containing Since are read
+	if len(forms) != 6 || forms[5]["page"] != "5" {
+		t.Errorf("Got %d requests, want pages 0 to 5", len(forms))
+	}
+}
+
+func TestActivityEventChanges(t *testing.T) {
+	var event ActivityEvent
+	err := json.Unmarshal([]byte(`{
+		"id": "955333384",
+		"object_type": "item",
+		"object_id": "2995104339",
+		"event_type": "updated",
+		"event_date": "2024-01-31T12:00:00.000000Z",
+		"parent_project_id": "2203306141",
+		"initiator_id": "1855589",
+		"extra_data": {
+			"content": "Buy milk",
+			"last_content": "Buy mlk",
+			"due_date": "2024-02-01T10:00:00Z",
+			"last_due_date": "",
+			"name": "unchanged",
+			"client": "Todoist/Web"
+		}
+	}`), &event)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []ActivityChange{
+		{Field: "content", Before: "Buy mlk", After: "Buy milk"},
+		{Field: "due_date", Before: "", After: "2024-02-01T10:00:00Z"},
+	}
+	if got := event.Changes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes() = %+v, want %+v", got, want)
+	}
+
+	if got, err := event.Time(); err != nil || !got.Equal(time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Time() = %v, %v", got, err)
+	}
+}
+
+func TestWriteActivity(t *testing.T) {
+	var forms []map[string]string
+	api := newServerClient(t, activityServer(t, 3, time.Minute, &forms))
+
+	var buffer bytes.Buffer
+	n, err := api.WriteActivity(context.Background(), &buffer, ActivityParameters{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Wrote %d events, want 3", n)
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(&buffer)
+	for scanner.Scan() {
+		var event ActivityEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Errorf("Line %d: %v", lines, err)
+		}
+		if event.ID != strconv.Itoa(lines) {
+			t.Errorf("Line %d has ID %q", lines, event.ID)
+		}
+		lines++
+	}
+	if lines != 3 {
+		t.Errorf("Got %d lines, want 3", lines)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// archivedPageSize is the number of archived projects requested per page.
+const archivedPageSize = 100
+
+// ArchiveProject archives the project for the given project_id, along
+// with its descendant projects.
+// See https://developer.todoist.com/sync/v9/#archive-a-project
+func (c *TodoistClient) ArchiveProject(ctx context.Context, project_id string) error {
+	if project_id == "" {
+		return fmt.Errorf("empty project ID")
+	}
+
+	_, err := c.executeCommand(ctx,
+		NewCommand("project_archive", map[string]string{"id": project_id}))
+
+	return err
+}
+
+// UnarchiveProject unarchives the project for the given project_id.
+// See https://developer.todoist.com/sync/v9/#unarchive-a-project
+func (c *TodoistClient) UnarchiveProject(ctx context.Context, project_id string) error {
+	if project_id == "" {
+		return fmt.Errorf("empty project ID")
+	}
+
+	_, err := c.executeCommand(ctx,
+		NewCommand("project_unarchive", map[string]string{"id": project_id}))
+
+	return err
+}
+
+// An ArchivedProjectIterator iterates over archived projects, requesting
+// pages from the API as needed.
+//
+//	it := client.ArchivedProjects(ctx)
+//	for it.Next() {
+//		project := it.Project()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ArchivedProjectIterator struct {
+	ctx      context.Context
+	client   *TodoistClient
+	page     []Project
+	project  Project
+	offset   int
+	lastPage bool
+	err      error
+}
+
+// ArchivedProjects returns an iterator over all archived projects.
+// See https://developer.todoist.com/sync/v9/#get-archived-projects
+func (c *TodoistClient) ArchivedProjects(ctx context.Context) *ArchivedProjectIterator {
+	return &ArchivedProjectIterator{ctx: ctx, client: c}
+}
+
+// Next advances to the next archived project, which is then available
+// from Project. Next returns false at the end or when an error occurs.
+func (it *ArchivedProjectIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if len(it.page) == 0 {
+		if it.lastPage {
+			return false
+		}
+
+		it.err = it.fetch()
+		if it.err != nil || len(it.page) == 0 {
+			return false
+		}
+	}
+
+	it.project, it.page = it.page[0], it.page[1:]
+
+	return true
+}
+
+// fetch requests the next page of archived projects.
+func (it *ArchivedProjectIterator) fetch() error {
+	form := url.Values{}
+	form.Set("limit", strconv.Itoa(archivedPageSize))
+	form.Set("offset", strconv.Itoa(it.offset))
+
+	body, err := it.client.SyncPost(it.ctx, "/projects/get_archived", form)
+	if err != nil {
+		return err
+	}
+
+	var projects []SyncProject
+	err = json.Unmarshal(body, &projects)
+	if err != nil {
+		return err
+	}
+
+	it.offset += len(projects)
+	it.lastPage = len(projects) < archivedPageSize
+
+	it.page = make([]Project, 0, len(projects))
+	for _, project := range projects {
+		p := project.Project()
+		p.IsArchived = true
+		it.page = append(it.page, p)
+	}
+
+	return nil
+}
+
+// Project returns the current archived project.
+func (it *ArchivedProjectIterator) Project() Project {
+	return it.project
+}
+
+// Err returns the first error that occurred, if any.
+func (it *ArchivedProjectIterator) Err() error {
+	return it.err
+}
+
+// GetArchivedProjects returns all archived projects.
+func (c *TodoistClient) GetArchivedProjects(ctx context.Context) ([]Project, error) {
+	var projects []Project
+
+	it := c.ArchivedProjects(ctx)
+	for it.Next() {
+		projects = append(projects, it.Project())
+	}
+
+	return projects, it.Err()
+}
+
+// ExcludeArchivedProjects returns projects without the archived projects.
+func ExcludeArchivedProjects(projects []Project) []Project {
+	active := make([]Project, 0, len(projects))
+	for _, project := range projects {
+		if !project.IsArchived {
+			active = append(active, project)
+		}
+	}
+
+	return active
+}
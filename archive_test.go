@@ -0,0 +1,81 @@
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestArchivedProjects(t *testing.T) {
+	const total = archivedPageSize + archivedPageSize/2
+
+	requests := 0
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sync/v9/projects/get_archived" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		requests++
+
+		offset, _ := strconv.Atoi(r.FormValue("offset"))
+		limit, _ := strconv.Atoi(r.FormValue("limit"))
+
+		projects := []map[string]interface{}{}
+		for n := offset; n < total && n < offset+limit; n++ {
+			projects = append(projects, map[string]interface{}{
+				"id":          strconv.Itoa(n),
+				"name":        "Project " + strconv.Itoa(n),
+				"child_order": n,
+				"is_archived": true,
+			})
+		}
+		json.NewEncoder(w).Encode(projects)
+	}))
+
+	projects, err := api.GetArchivedProjects(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(projects) != total {
+		t.Fatalf("Got %d projects, want %d", len(projects), total)
+	}
+	if requests != 2 {
+		t.Errorf("Got %d requests, want 2", requests)
+	}
+	for n, project := range projects {
+		if project.ID != strconv.Itoa(n) || project.Order != n || !project.IsArchived {
+			t.Errorf("Unexpected project %d: %+v", n, project)
+		}
+	}
+
+	if active := ExcludeArchivedProjects(append(projects, Project{ID: "x"})); len(active) != 1 {
+		t.Errorf("ExcludeArchivedProjects() returned %d projects, want 1", len(active))
+	}
+}
+
+func TestArchiveProject(t *testing.T) {
+	var commands []Command
+
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.Unmarshal([]byte(r.FormValue("commands")), &commands)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_status": map[string]string{commands[0].UUID: "ok"},
+		})
+	}))
+
+	if err := api.ArchiveProject(context.Background(), "1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if commands[0].Type != "project_archive" {
+		t.Errorf("Type = %q, want %q", commands[0].Type, "project_archive")
+	}
+
+	if err := api.UnarchiveProject(context.Background(), "1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if commands[0].Type != "project_unarchive" {
+		t.Errorf("Type = %q, want %q", commands[0].Type, "project_unarchive")
+	}
+}
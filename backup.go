@@ -0,0 +1,324 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// BackupManifestFileName is the name of the manifest written by
+	// SyncBackups.
+	BackupManifestFileName = "backups.json"
+
+	// backupVersionLayout is the layout of Backup.Version.
+	backupVersionLayout = "2006-01-02 15:04"
+
+	// partialSuffix is appended to the name of an incomplete download.
+	partialSuffix = ".partial"
+)
+
+// A Backup is an automatic backup of the account.
+// See https://developer.todoist.com/sync/v9/#backups
+type Backup struct {
+	// Version is the time of the backup in UTC, e.g., "2024-01-31 02:03".
+	Version string `json:"version"`
+	URL     string `json:"url"`
+}
+
+// Time returns the time of the backup.
+func (b Backup) Time() (time.Time, error) {
+	return time.Parse(backupVersionLayout, b.Version)
+}
+
+// FileName returns the file name of the backup, which sorts in the same
+// order as Version, e.g., "todoist-backup-2024-01-31_0203.zip".
+func (b Backup) FileName() string {
+	version := strings.NewReplacer(" ", "_", ":", "").Replace(b.Version)
+	return "todoist-backup-" + safeName(version, "unknown") + ".zip"
+}
+
+// ListBackups returns the available backups, oldest first.
+func (c *TodoistClient) ListBackups(ctx context.Context) ([]Backup, error) {
+	body, err := c.SyncPost(ctx, "/backups/get", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	err = json.Unmarshal(body, &backups)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].Version < backups[j].Version
+	})
+
+	return backups, nil
+}
+
+// DownloadBackup writes the ZIP file of the backup to w, returning the
+// number of bytes written.
+func (c *TodoistClient) DownloadBackup(ctx context.Context, backup Backup, w io.Writer) (int64, error) {
+	if backup.URL == "" {
+		return 0, fmt.Errorf("backup has no URL")
+	}
+
+	resp, err := c.getFile(ctx, backup.URL, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return io.Copy(w, resp.Body)
+}
+
+// A BackupEntry records a downloaded backup.
+type BackupEntry struct {
+	Version string `json:"version"`
+	Size    int64  `json:"size"`
+	SHA256  string `json:"sha256"`
+}
+
+// A BackupManifest records the downloaded backups by file name.
+type BackupManifest map[string]BackupEntry
+
+// A BackupSyncResult reports the result of SyncBackups.
+type BackupSyncResult struct {
+	// Manifest records all downloaded backups.
+	Manifest BackupManifest
+
+	// Latest is the version of the latest verified local backup before
+	// the sync, or empty if there was none.
+	Latest string
+
+	// Downloaded are the file names of the downloaded backups.
+	Downloaded []string
+
+	// Failed are the errors for the file names of backups that could
+	// not be downloaded or verified.
+	Failed map[string]error
+}
+
+// readBackupManifest reads the backup manifest in dir, returning an empty
+// manifest if it does not exist.
+func readBackupManifest(dir string) (BackupManifest, error) {
+	manifest := make(BackupManifest)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, BackupManifestFileName))
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// writeBackupManifest atomically replaces the backup manifest in dir.
+func writeBackupManifest(dir string, manifest BackupManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(data, filepath.Join(dir, BackupManifestFileName))
+}
+
+// latestBackup returns the latest version in manifest whose file in dir
+// matches its recorded SHA-256.
+func latestBackup(dir string, manifest BackupManifest) string {
+	var latest string
+
+	for fileName, entry := range manifest {
+		if entry.Version <= latest {
+			continue
+		}
+
+		sum, err := fileSHA256(filepath.Join(dir, fileName))
+		if err == nil && sum == entry.SHA256 {
+			latest = entry.Version
+		}
+	}
+
+	return latest
+}
+
+// verifyZip checks that name is a ZIP file whose contents match their
+// CRC-32 checksums.
+func verifyZip(name string) error {
+	r, err := zip.OpenReader(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+
+		// the checksum is verified when the end of the file is read
+		_, err = io.Copy(ioutil.Discard, rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// downloadResumable downloads rawURL to name, continuing a previous partial
+// download if the server supports it.
+func (c *TodoistClient) downloadResumable(ctx context.Context, rawURL string, name string) error {
+	partial := name + partialSuffix
+
+	var offset int64
+	if info, err := os.Stat(partial); err == nil {
+		offset = info.Size()
+	}
+
+	resp, err := c.getFile(ctx, rawURL, offset)
+	var apiErr *APIErrorResponse
+	if offset > 0 && errors.As(err, &apiErr) &&
+		apiErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		// the partial file cannot be continued, so start again
+		offset = 0
+		resp, err = c.getFile(ctx, rawURL, 0)
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if offset > 0 && resp.StatusCode == http.StatusPartialContent {
+		flag = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+
+	file, err := os.OpenFile(partial, flag, 0o644)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(partial, name)
+}
+
+// SyncBackups downloads the backups newer than the latest local backup
+// into dir and records them in a manifest file named
+// BackupManifestFileName with the SHA-256 of each file.
+//
+// A local backup is only used as the latest if it matches the manifest.
+// Interrupted downloads are continued on the next run. Each download is
+// verified as a ZIP file before it is added to the manifest. Failed
+// downloads are reported in the result and do not stop other downloads.
+// An error is returned only if the backups cannot be listed or the
+// manifest cannot be read or written.
+func (c *TodoistClient) SyncBackups(ctx context.Context, dir string) (*BackupSyncResult, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := readBackupManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	backups, err := c.ListBackups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BackupSyncResult{
+		Manifest: manifest,
+		Latest:   latestBackup(dir, manifest),
+		Failed:   make(map[string]error),
+	}
+
+	for _, backup := range backups {
+		if backup.Version <= result.Latest {
+			continue
+		}
+
+		fileName := backup.FileName()
+		name := filepath.Join(dir, fileName)
+
+		err := c.downloadResumable(ctx, backup.URL, name)
+		if err == nil {
+			err = verifyZip(name)
+			if err != nil {
+				os.Remove(name)
+			}
+		}
+		if err != nil {
+			result.Failed[fileName] = err
+			continue
+		}
+
+		entry := BackupEntry{Version: backup.Version}
+		entry.SHA256, err = fileSHA256(name)
+		if err != nil {
+			result.Failed[fileName] = err
+			continue
+		}
+		if info, err := os.Stat(name); err == nil {
+			entry.Size = info.Size()
+		}
+
+		manifest[fileName] = entry
+		result.Downloaded = append(result.Downloaded, fileName)
+
+		// record each backup so an interrupted sync keeps its progress
+		err = writeBackupManifest(dir, manifest)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,209 @@
+package tdapi
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// zipData returns a ZIP file containing a single file with content.
+func zipData(t *testing.T, content string) []byte {
+	var buffer bytes.Buffer
+
+	w := zip.NewWriter(&buffer)
+	f, err := w.Create("Inbox.csv")
+	if err != nil {
+		t.Fatalf("Cannot create ZIP: %v", err)
+	}
+	f.Write([]byte(content))
+	if err := w.Close(); err != nil {
+		t.Fatalf("Cannot create ZIP: %v", err)
+	}
+
+	return buffer.Bytes()
+}
+
+// backupServer serves the backups in files, keyed by version, and records
+// the Range header of each file request.
+type backupServer struct {
+	t      *testing.T
+	mu     sync.Mutex
+	files  map[string][]byte
+	ranges map[string]string
+}
+
+func (s *backupServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == "/sync/v9/backups/get" {
+		var backups []Backup
+		for version := range s.files {
+			backups = append(backups, Backup{
+				Version: version,
+				URL:     "https://todoist.com/backups/" + strings.Replace(version, " ", "_", 1) + ".zip",
+			})
+		}
+		json.NewEncoder(w).Encode(backups)
+		return
+	}
+
+	version := strings.Replace(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/backups/"), ".zip"), "_", " ", 1)
+	data, ok := s.files[version]
+	if !ok {
+		s.t.Errorf("Unexpected request %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+	s.ranges[version] = r.Header.Get("Range")
+
+	http.ServeContent(w, r, "backup.zip", time.Time{}, bytes.NewReader(data))
+}
+
+func TestListBackups(t *testing.T) {
+	server := &backupServer{t: t, files: map[string][]byte{
+		"2024-01-31 02:03": nil,
+		"2024-01-29 02:03": nil,
+		"2024-01-30 02:03": nil,
+	}, ranges: map[string]string{}}
+	api := newServerClient(t, server)
+
+	backups, err := api.ListBackups(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var versions []string
+	for _, backup := range backups {
+		versions = append(versions, backup.Version)
+	}
+	want := []string{"2024-01-29 02:03", "2024-01-30 02:03", "2024-01-31 02:03"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("Versions = %v, want %v", versions, want)
+	}
+
+	if got := backups[0].FileName(); got != "todoist-backup-2024-01-29_0203.zip" {
+		t.Errorf("FileName() = %q", got)
+	}
+	if got, err := backups[0].Time(); err != nil || !got.Equal(time.Date(2024, 1, 29, 2, 3, 0, 0, time.UTC)) {
+		t.Errorf("Time() = %v, %v", got, err)
+	}
+}
+
+func TestDownloadBackup(t *testing.T) {
+	data := zipData(t, "backup")
+	server := &backupServer{t: t, files: map[string][]byte{"2024-01-31 02:03": data}, ranges: map[string]string{}}
+	api := newServerClient(t, server)
+
+	var buffer bytes.Buffer
+	backup := Backup{Version: "2024-01-31 02:03", URL: "https://todoist.com/backups/2024-01-31_02:03.zip"}
+	n, err := api.DownloadBackup(context.Background(), backup, &buffer)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != int64(len(data)) || !bytes.Equal(buffer.Bytes(), data) {
+		t.Errorf("Downloaded %d bytes, want %d", n, len(data))
+	}
+
+	if _, err := api.DownloadBackup(context.Background(), Backup{}, &buffer); err == nil {
+		t.Errorf("Expected error for backup without URL")
+	}
+}
+
+func TestSyncBackups(t *testing.T) {
+	server := &backupServer{t: t, files: map[string][]byte{
+		"2024-01-29 02:03": zipData(t, "first"),
+		"2024-01-30 02:03": zipData(t, "second"),
+	}, ranges: map[string]string{}}
+	api := newServerClient(t, server)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	result, err := api.SyncBackups(ctx, dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{"todoist-backup-2024-01-29_0203.zip", "todoist-backup-2024-01-30_0203.zip"}
+	if result.Latest != "" || !reflect.DeepEqual(result.Downloaded, want) || len(result.Failed) != 0 {
+		t.Errorf("Unexpected first result: %+v", result)
+	}
+
+	// a new backup with an interrupted download and a corrupt backup
+	third := zipData(t, strings.Repeat("third ", 1000))
+	server.mu.Lock()
+	server.files["2024-01-31 02:03"] = third
+	server.files["2024-02-01 02:03"] = []byte("not a zip file")
+	server.mu.Unlock()
+
+	partial := filepath.Join(dir, "todoist-backup-2024-01-31_0203.zip"+partialSuffix)
+	if err := ioutil.WriteFile(partial, third[:100], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = api.SyncBackups(ctx, dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want = []string{"todoist-backup-2024-01-31_0203.zip"}
+	if result.Latest != "2024-01-30 02:03" || !reflect.DeepEqual(result.Downloaded, want) {
+		t.Errorf("Unexpected second result: %+v", result)
+	}
+	if _, ok := result.Failed["todoist-backup-2024-02-01_0203.zip"]; !ok || len(result.Failed) != 1 {
+		t.Errorf("Failed = %v, want corrupt backup", result.Failed)
+	}
+	if got := server.ranges["2024-01-31 02:03"]; got != "bytes=100-" {
+		t.Errorf("Range = %q, want %q", got, "bytes=100-")
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, want[0]))
+	if err != nil || !bytes.Equal(data, third) {
+		t.Errorf("Resumed download does not match")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "todoist-backup-2024-02-01_0203.zip")); !os.IsNotExist(err) {
+		t.Errorf("Corrupt backup was kept: %v", err)
+	}
+
+	manifest, err := readBackupManifest(dir)
+	if err != nil || len(manifest) != 3 || manifest[want[0]].Size != int64(len(third)) {
+		t.Errorf("Unexpected manifest: %+v, %v", manifest, err)
+	}
+
+	// a modified local backup is not used as the latest
+	ioutil.WriteFile(filepath.Join(dir, want[0]), []byte("modified"), 0o644)
+	delete(server.files, "2024-02-01 02:03")
+	result, err = api.SyncBackups(ctx, dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Latest != "2024-01-30 02:03" || !reflect.DeepEqual(result.Downloaded, want) {
+		t.Errorf("Unexpected third result: %+v", result)
+	}
+	// the manifest is replaced without leaving temporary files behind
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	wantNames := []string{
+		BackupManifestFileName,
+		"todoist-backup-2024-01-29_0203.zip",
+		"todoist-backup-2024-01-30_0203.zip",
+		"todoist-backup-2024-01-31_0203.zip",
+	}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("Directory contains %v, want %v", names, wantNames)
+	}
+}
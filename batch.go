@@ -0,0 +1,298 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxCommandsPerRequest is the maximum number of commands the Sync API
+// accepts in a single request.
+const MaxCommandsPerRequest = 100
+
+// ErrCommandNotSent is the status of a command that was not sent because
+// an earlier request in the batch failed.
+var ErrCommandNotSent = errors.New("command not sent")
+
+// ProjectAddArgs are the arguments of a project_add command.
+// See https://developer.todoist.com/sync/v9/#add-a-project
+type ProjectAddArgs struct {
+	Name       string `json:"name"`
+	Color      string `json:"color,omitempty"`
+	ParentID   string `json:"parent_id,omitempty"`
+	ChildOrder int    `json:"child_order,omitempty"`
+	IsFavorite bool   `json:"is_favorite,omitempty"`
+	ViewStyle  string `json:"view_style,omitempty"`
+}
+
+// SectionAddArgs are the arguments of a section_add command.
+// See https://developer.todoist.com/sync/v9/#add-a-section
+type SectionAddArgs struct {
+	Name         string `json:"name"`
+	ProjectID    string `json:"project_id"`
+	SectionOrder int    `json:"section_order,omitempty"`
+}
+
+// ItemAddArgs are the arguments of an item_add command.
+// See https://developer.todoist.com/sync/v9/#add-an-item
+type ItemAddArgs struct {
+	Content        string        `json:"content"`
+	Description    string        `json:"description,omitempty"`
+	ProjectID      string        `json:"project_id,omitempty"`
+	SectionID      string        `json:"section_id,omitempty"`
+	ParentID       string        `json:"parent_id,omitempty"`
+	ChildOrder     int           `json:"child_order,omitempty"`
+	Labels         []string      `json:"labels,omitempty"`
+	Priority       int           `json:"priority,omitempty"`
+	Due            *DueRequest   `json:"due,omitempty"`
+	ResponsibleUID string        `json:"responsible_uid,omitempty"`
+	Duration       *TaskDuration `json:"duration,omitempty"`
+	Deadline       *TaskDeadline `json:"deadline,omitempty"`
+}
+
+// NoteAddArgs are the arguments of a note_add command. Exactly one of
+// ItemID or ProjectID must be set.
+// See https://developer.todoist.com/sync/v9/#add-a-note
+type NoteAddArgs struct {
+	ItemID         string      `json:"item_id,omitempty"`
+	ProjectID      string      `json:"project_id,omitempty"`
+	Content        string      `json:"content"`
+	FileAttachment *Attachment `json:"file_attachment,omitempty"`
+}
+
+// A CommandBatch queues Sync API commands to send together. Commands that
+// create an object are given a temp ID, which later commands in the batch
+// can use in place of the real ID of the object.
+//
+//	batch := tdapi.NewCommandBatch()
+//	projectID := batch.AddProject(tdapi.ProjectAddArgs{Name: "Launch"})
+//	batch.AddItem(tdapi.ItemAddArgs{Content: "Plan", ProjectID: projectID})
+//	result, err := client.ExecuteBatch(ctx, batch)
+type CommandBatch struct {
+	commands []Command
+}
+
+// NewCommandBatch returns an empty CommandBatch.
+func NewCommandBatch() *CommandBatch {
+	return &CommandBatch{}
+}
+
+// Len returns the number of queued commands.
+func (b *CommandBatch) Len() int {
+	return len(b.commands)
+}
+
+// Commands returns the queued commands.
+func (b *CommandBatch) Commands() []Command {
+	return append([]Command(nil), b.commands...)
+}
+
+// Add queues a command of cmdType with args, returning its UUID.
+func (b *CommandBatch) Add(cmdType string, args interface{}) string {
+	cmd := NewCommand(cmdType, args)
+	b.commands = append(b.commands, cmd)
+
+	return cmd.UUID
+}
+
+// AddWithTempID queues a command of cmdType with args that creates an
+// object, returning the temp ID of the object.
+func (b *CommandBatch) AddWithTempID(cmdType string, args interface{}) string {
+	cmd := NewCommand(cmdType, args)
+	cmd.TempID = newUUID()
+	b.commands = append(b.commands, cmd)
+
+	return cmd.TempID
+}
+
+// AddProject queues a project_add command, returning the temp ID of the project.
+func (b *CommandBatch) AddProject(args ProjectAddArgs) string {
+	return b.AddWithTempID("project_add", args)
+}
+
+// AddSection queues a section_add command, returning the temp ID of the section.
+func (b *CommandBatch) AddSection(args SectionAddArgs) string {
+	return b.AddWithTempID("section_add", args)
+}
+
+// AddItem queues an item_add command, returning the temp ID of the task.
+func (b *CommandBatch) AddItem(args ItemAddArgs) string {
+	return b.AddWithTempID("item_add", args)
+}
+
+// AddNote queues a note_add command, returning the temp ID of the comment.
+func (b *CommandBatch) AddNote(args NoteAddArgs) string {
+	return b.AddWithTempID("note_add", args)
+}
+
+// A CommandStatus is the result of a command in a batch.
+type CommandStatus struct {
+	Command Command
+
+	// Err is nil if the command succeeded, a *CommandError if the command
+	// failed, or ErrCommandNotSent if the command was not sent.
+	Err error
+}
+
+// A BatchResult is the result of ExecuteBatch.
+type BatchResult struct {
+	// TempIDMapping maps the temp IDs of created objects to real IDs.
+	TempIDMapping map[string]string
+
+	// Statuses are the status of each command, in the order queued.
+	Statuses []CommandStatus
+}
+
+// ID returns the real ID for tempID, or tempID if it is not mapped.
+func (r *BatchResult) ID(tempID string) string {
+	if id, ok := r.TempIDMapping[tempID]; ok {
+		return id
+	}
+	return tempID
+}
+
+// Failed returns the status of each command that failed or was not sent.
+func (r *BatchResult) Failed() []CommandStatus {
+	var failed []CommandStatus
+	for _, status := range r.Statuses {
+		if status.Err != nil {
+			failed = append(failed, status)
+		}
+	}
+	return failed
+}
+
+// Err returns a *BatchError if any command failed or was not sent, or nil
+// if all commands succeeded.
+func (r *BatchResult) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	return &BatchError{Failed: failed}
+}
+
+// A BatchError reports the commands of a batch that failed.
+type BatchError struct {
+	Failed []CommandStatus
+}
+
+// Error returns a string representation of the error.
+func (e *BatchError) Error() string {
+	msgs := make([]string, 0, len(e.Failed))
+	for _, status := range e.Failed {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", status.Command.Type, status.Err))
+	}
+
+	return fmt.Sprintf("%d commands failed: %s",
+		len(e.Failed), strings.Join(msgs, "; "))
+}
+
+// replaceTempIDs returns args with any string that is a temp ID in mapping
+// replaced by its real ID.
+func replaceTempIDs(args interface{}, mapping map[string]string) (interface{}, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	err = json.Unmarshal(data, &v)
+	if err != nil {
+		return nil, err
+	}
+
+	var replace func(v interface{}) interface{}
+	replace = func(v interface{}) interface{} {
+		switch v := v.(type) {
+		case string:
+			if id, ok := mapping[v]; ok {
+				return id
+			}
+		case []interface{}:
+			for n := range v {
+				v[n] = replace(v[n])
+			}
+		case map[string]interface{}:
+			for key := range v {
+				v[key] = replace(v[key])
+			}
+		}
+		return v
+	}
+
+	return replace(v), nil
+}
+
+// ExecuteBatch sends the queued commands, split into requests of at most
+// MaxCommandsPerRequest commands. Temp IDs created by an earlier request
+// are replaced by their real IDs in later requests.
+//
+// The returned error is only for a failed request, in which case the
+// result includes the commands that were sent and the remaining commands
+// have ErrCommandNotSent. Use BatchResult.Err to check for commands that
+// failed.
+func (c *TodoistClient) ExecuteBatch(ctx context.Context, b *CommandBatch) (*BatchResult, error) {
+	result := &BatchResult{
+		TempIDMapping: make(map[string]string),
+		Statuses:      make([]CommandStatus, len(b.commands)),
+	}
+	for n, cmd := range b.commands {
+		result.Statuses[n] = CommandStatus{Command: cmd, Err: ErrCommandNotSent}
+	}
+
+	for start := 0; start < len(b.commands); start += MaxCommandsPerRequest {
+		end := start + MaxCommandsPerRequest
+		if end > len(b.commands) {
+			end = len(b.commands)
+		}
+
+		cmds := make([]Command, 0, end-start)
+		for _, cmd := range b.commands[start:end] {
+			if start > 0 && len(result.TempIDMapping) > 0 {
+				args, err := replaceTempIDs(cmd.Args, result.TempIDMapping)
+				if err != nil {
+					return result, err
+				}
+				cmd.Args = args
+			}
+			cmds = append(cmds, cmd)
+		}
+
+		response, err := c.ExecuteCommands(ctx, cmds)
+		if err != nil {
+			return result, err
+		}
+
+		for tempID, id := range response.TempIDMapping {
+			result.TempIDMapping[tempID] = id
+		}
+
+		for n, cmd := range cmds {
+			result.Statuses[start+n] = CommandStatus{
+				Command: cmd,
+				Err:     response.Err(cmd.UUID),
+			}
+		}
+	}
+
+	return result, nil
+}
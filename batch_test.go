@@ -0,0 +1,159 @@
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestExecuteBatch(t *testing.T) {
+	const items = MaxCommandsPerRequest + 20
+
+	batch := NewCommandBatch()
+	projectTempID := batch.AddProject(ProjectAddArgs{Name: "Launch"})
+	for n := 0; n < items; n++ {
+		batch.AddItem(ItemAddArgs{Content: "Task " + strconv.Itoa(n), ProjectID: projectTempID})
+	}
+
+	var requests []int
+
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var commands []struct {
+			Type   string                 `json:"type"`
+			UUID   string                 `json:"uuid"`
+			TempID string                 `json:"temp_id"`
+			Args   map[string]interface{} `json:"args"`
+		}
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Cannot decode commands: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, len(commands))
+
+		status := make(map[string]interface{})
+		mapping := make(map[string]string)
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+			mapping[cmd.TempID] = "real-" + cmd.TempID
+
+			if cmd.Type == "item_add" {
+				projectID := cmd.Args["project_id"]
+				// the first request uses the temp ID, later requests the real ID
+				if len(requests) == 1 && projectID != projectTempID ||
+					len(requests) > 1 && projectID != "real-"+projectTempID {
+					t.Errorf("Request %d project_id = %v", len(requests), projectID)
+				}
+			}
+			if cmd.Args["content"] == "Task 5" {
+				status[cmd.UUID] = map[string]interface{}{"error_code": 15, "error": "Invalid temporary id"}
+				delete(mapping, cmd.TempID)
+			}
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_status":     status,
+			"temp_id_mapping": mapping,
+		})
+	}))
+
+	result, err := api.ExecuteBatch(context.Background(), batch)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(requests) != 2 || requests[0] != MaxCommandsPerRequest || requests[1] != items+1-MaxCommandsPerRequest {
+		t.Errorf("Commands per request = %v", requests)
+	}
+
+	if got := result.ID(projectTempID); got != "real-"+projectTempID {
+		t.Errorf("ID() = %q, want real ID", got)
+	}
+	if len(result.TempIDMapping) != items {
+		t.Errorf("Got %d temp IDs, want %d", len(result.TempIDMapping), items)
+	}
+
+	var batchErr *BatchError
+	if !errors.As(result.Err(), &batchErr) || len(batchErr.Failed) != 1 {
+		t.Fatalf("Err() = %v, want one failed command", result.Err())
+	}
+
+	var cmdErr *CommandError
+	if !errors.As(batchErr.Failed[0].Err, &cmdErr) || cmdErr.ErrorCode != 15 {
+		t.Errorf("Failed command error = %v", batchErr.Failed[0].Err)
+	}
+	if result.Statuses[6].Err == nil || result.Statuses[6].Command.Type != "item_add" {
+		t.Errorf("Status for Task 5 = %+v", result.Statuses[6])
+	}
+}
+
+func TestExecuteBatchRequestFailure(t *testing.T) {
+	batch := NewCommandBatch()
+	for n := 0; n < MaxCommandsPerRequest+1; n++ {
+		batch.AddItem(ItemAddArgs{Content: "Task"})
+	}
+
+	calls := 0
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var commands []Command
+		json.Unmarshal([]byte(r.FormValue("commands")), &commands)
+		status := make(map[string]string)
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"sync_status": status})
+	}))
+
+	result, err := api.ExecuteBatch(context.Background(), batch)
+	if err == nil {
+		t.Fatalf("Expected error for failed request")
+	}
+
+	failed := result.Failed()
+	if len(failed) != 1 || failed[0].Err != ErrCommandNotSent {
+		t.Errorf("Failed() = %+v, want one command not sent", failed)
+	}
+}
+
+func TestAddItemDue(t *testing.T) {
+	batch := NewCommandBatch()
+	batch.AddItem(ItemAddArgs{Content: "Task", Due: &DueRequest{String: "every monday"}})
+
+	var args string
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var commands []struct {
+			UUID string          `json:"uuid"`
+			Args json.RawMessage `json:"args"`
+		}
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil || len(commands) != 1 {
+			t.Errorf("Cannot decode commands: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		args = string(commands[0].Args)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_status": map[string]interface{}{commands[0].UUID: "ok"},
+		})
+	}))
+
+	result, err := api.ExecuteBatch(context.Background(), batch)
+	if err != nil || result.Err() != nil {
+		t.Fatalf("Unexpected error: %v, %v", err, result.Err())
+	}
+
+	// only the fields of the due date that are set are sent
+	want := `{"content":"Task","due":{"string":"every monday"}}`
+	if args != want {
+		t.Errorf("Args = %s, want %s", args, want)
+	}
+}
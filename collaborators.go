@@ -0,0 +1,215 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A Collaborator is a user who shares a project.
+// See https://developer.todoist.com/rest/v2/?shell#get-all-collaborators
+type Collaborator struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// A NotCollaboratorError is returned when a person cannot be resolved to a
+// collaborator on a project.
+type NotCollaboratorError struct {
+	Person    string
+	ProjectID string
+}
+
+// Error returns a string representation of the error.
+func (e *NotCollaboratorError) Error() string {
+	if e.ProjectID == "" {
+		return fmt.Sprintf("%q is not a collaborator on any shared project", e.Person)
+	}
+	return fmt.Sprintf("%q is not a collaborator on project %s", e.Person, e.ProjectID)
+}
+
+// GetCollaborators returns the collaborators of the shared project with
+// projectID.
+func (c *TodoistClient) GetCollaborators(projectID string) (response []Collaborator, err error) {
+	var body []byte
+
+	if projectID == "" {
+		return response, fmt.Errorf("empty project ID")
+	}
+
+	body, err = c.Get("/projects/"+projectID+"/collaborators", nil)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, &response)
+
+	return response, err
+}
+
+// A CollaboratorResolver maps a collaborator name or email to a
+// Collaborator across shared projects. Names and emails are matched
+// without regard to case or surrounding whitespace.
+type CollaboratorResolver struct {
+	byProjectID map[string][]Collaborator
+}
+
+// NewCollaboratorResolver returns a CollaboratorResolver for the
+// collaborators of each project ID.
+func NewCollaboratorResolver(byProjectID map[string][]Collaborator) *CollaboratorResolver {
+	return &CollaboratorResolver{byProjectID: byProjectID}
+}
+
+// GetCollaboratorResolver returns a CollaboratorResolver for all shared
+// projects.
+func (c *TodoistClient) GetCollaboratorResolver() (*CollaboratorResolver, error) {
+	projects, err := c.GetAllProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	byProjectID := make(map[string][]Collaborator)
+	for _, project := range projects {
+		if !project.IsShared {
+			continue
+		}
+
+		collaborators, err := c.GetCollaborators(project.ID)
+		if err != nil {
+			return nil, err
+		}
+		byProjectID[project.ID] = collaborators
+	}
+
+	return NewCollaboratorResolver(byProjectID), nil
+}
+
+// Collaborators returns the collaborators of the project with projectID.
+func (r *CollaboratorResolver) Collaborators(projectID string) []Collaborator {
+	return r.byProjectID[projectID]
+}
+
+// matches reports whether collaborator has the name or email of person.
+func (collaborator Collaborator) matches(person string) bool {
+	person = strings.TrimSpace(person)
+	return strings.EqualFold(collaborator.Email, person) ||
+		strings.EqualFold(strings.TrimSpace(collaborator.Name), person)
+}
+
+// resolve returns the single collaborator matching person, preferring an
+// email match over a name match.
+func resolve(collaborators []Collaborator, person string, projectID string) (Collaborator, error) {
+	for _, collaborator := range collaborators {
+		if strings.EqualFold(collaborator.Email, strings.TrimSpace(person)) {
+			return collaborator, nil
+		}
+	}
+
+	matchByID := make(map[string]Collaborator)
+	for _, collaborator := range collaborators {
+		if collaborator.matches(person) {
+			matchByID[collaborator.ID] = collaborator
+		}
+	}
+
+	switch len(matchByID) {
+	case 0:
+		return Collaborator{}, &NotCollaboratorError{Person: person, ProjectID: projectID}
+	case 1:
+		for _, collaborator := range matchByID {
+			return collaborator, nil
+		}
+	}
+
+	ids := make([]string, 0, len(matchByID))
+	for id := range matchByID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return Collaborator{}, fmt.Errorf("%q matches more than one collaborator: %s",
+		person, strings.Join(ids, ", "))
+}
+
+// Resolve returns the collaborator with the name or email of person on any
+// shared project. An error is returned if no collaborator matches or if a
+// name matches more than one collaborator.
+func (r *CollaboratorResolver) Resolve(person string) (Collaborator, error) {
+	var all []Collaborator
+	for _, collaborators := range r.byProjectID {
+		all = append(all, collaborators...)
+	}
+
+	return resolve(all, person, "")
+}
+
+// ResolveInProject returns the collaborator with the name or email of
+// person on the project with projectID. A *NotCollaboratorError is
+// returned if person is not a collaborator on the project.
+func (r *CollaboratorResolver) ResolveInProject(projectID string, person string) (Collaborator, error) {
+	return resolve(r.byProjectID[projectID], person, projectID)
+}
+
+// CreateTaskWithAssignee creates a new task assigned to the collaborator
+// with the name or email of assignee on the project of the task.
+func (c *TodoistClient) CreateTaskWithAssignee(r TaskRequest, assignee string, resolver *CollaboratorResolver) (Task, error) {
+	if r.ProjectID == "" {
+		return Task{}, fmt.Errorf("a shared project ID is required to assign a task")
+	}
+
+	collaborator, err := resolver.ResolveInProject(r.ProjectID, assignee)
+	if err != nil {
+		return Task{}, err
+	}
+
+	r.AssigneeID = collaborator.ID
+
+	return c.CreateTask(r)
+}
+
+// UpdateTaskWithAssignee updates the task with id, assigning it to the
+// collaborator with the name or email of assignee on the project of the
+// task.
+func (c *TodoistClient) UpdateTaskWithAssignee(id string, r TaskRequest, assignee string, resolver *CollaboratorResolver) (Task, error) {
+	if id == "" {
+		return Task{}, fmt.Errorf("empty task ID")
+	}
+
+	body, err := c.Get("/tasks/"+id, nil)
+	if err != nil {
+		return Task{}, err
+	}
+
+	var task Task
+	err = json.Unmarshal(body, &task)
+	if err != nil {
+		return Task{}, err
+	}
+
+	collaborator, err := resolver.ResolveInProject(task.ProjectID, assignee)
+	if err != nil {
+		return Task{}, err
+	}
+
+	r.AssigneeID = collaborator.ID
+
+	return c.UpdateTask(id, r)
+}
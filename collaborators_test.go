@@ -0,0 +1,138 @@
+package tdapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func testResolver() *CollaboratorResolver {
+	return NewCollaboratorResolver(map[string][]Collaborator{
+		"p1": {
+			{ID: "1", Name: "Alice Smith", Email: "alice@example.com"},
+			{ID: "2", Name: "Bob Jones", Email: "bob@example.com"},
+		},
+		"p2": {
+			{ID: "2", Name: "Bob Jones", Email: "bob@example.com"},
+			{ID: "3", Name: "Bob Jones", Email: "bob.jones@example.org"},
+		},
+	})
+}
+
+func TestCollaboratorResolver(t *testing.T) {
+	resolver := testResolver()
+
+	tests := []struct {
+		name      string
+		projectID string
+		person    string
+		wantID    string
+		wantErr   bool
+	}{
+		{"email", "", "ALICE@example.com", "1", false},
+		{"name", "", " alice smith ", "1", false},
+		{"ambiguous name", "", "Bob Jones", "", true},
+		{"email disambiguates", "", "bob.jones@example.org", "3", false},
+		{"unknown", "", "carol@example.com", "", true},
+		{"name in project", "p1", "Bob Jones", "2", false},
+		{"not on project", "p1", "bob.jones@example.org", "", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got Collaborator
+			var err error
+			if tc.projectID == "" {
+				got, err = resolver.Resolve(tc.person)
+			} else {
+				got, err = resolver.ResolveInProject(tc.projectID, tc.person)
+			}
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got.ID != tc.wantID {
+				t.Errorf("ID = %q, want %q", got.ID, tc.wantID)
+			}
+		})
+	}
+}
+
+func TestCreateTaskWithAssignee(t *testing.T) {
+	var request map[string]interface{}
+
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &request)
+		io.WriteString(w, `{"id":"9","project_id":"p1","content":"Review","assignee_id":"2"}`)
+	}))
+
+	task, err := api.CreateTaskWithAssignee(
+		TaskRequest{Content: "Review", ProjectID: "p1"}, "bob@example.com", testResolver())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request["assignee_id"] != "2" || task.AssigneeID != "2" {
+		t.Errorf("assignee_id = %v, task.AssigneeID = %q, want 2", request["assignee_id"], task.AssigneeID)
+	}
+
+	request = nil
+	_, err = api.CreateTaskWithAssignee(
+		TaskRequest{Content: "Review", ProjectID: "p2"}, "alice@example.com", testResolver())
+
+	var notCollaborator *NotCollaboratorError
+	if !errors.As(err, &notCollaborator) || notCollaborator.ProjectID != "p2" {
+		t.Errorf("Err = %v, want *NotCollaboratorError for p2", err)
+	}
+	if request != nil {
+		t.Errorf("Unexpected request for task with unknown assignee")
+	}
+}
+
+func TestUpdateTaskWithAssignee(t *testing.T) {
+	var updates []map[string]interface{}
+
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/v2/tasks/9":
+			io.WriteString(w, `{"id":"9","project_id":"p1","content":"Review"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/v2/tasks/10":
+			io.WriteString(w, `{"id":"10","project_id":"p2","content":"Plan"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/v2/tasks/9":
+			var update map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&update)
+			updates = append(updates, update)
+			io.WriteString(w, `{"id":"9","project_id":"p1","content":"Review today","assignee_id":"2"}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+
+	// the assignee is resolved on the project of the task
+	task, err := api.UpdateTaskWithAssignee("9",
+		TaskRequest{Content: "Review today"}, "Bob Jones", testResolver())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(updates) != 1 || updates[0]["assignee_id"] != "2" || updates[0]["content"] != "Review today" {
+		t.Errorf("Updates = %v, want content and assignee_id 2", updates)
+	}
+	if task.AssigneeID != "2" {
+		t.Errorf("task.AssigneeID = %q, want 2", task.AssigneeID)
+	}
+
+	updates = nil
+	_, err = api.UpdateTaskWithAssignee("10",
+		TaskRequest{Content: "Plan"}, "alice@example.com", testResolver())
+
+	var notCollaborator *NotCollaboratorError
+	if !errors.As(err, &notCollaborator) || notCollaborator.ProjectID != "p2" {
+		t.Errorf("Err = %v, want *NotCollaboratorError for p2", err)
+	}
+	if updates != nil {
+		t.Errorf("Unexpected update for task with unknown assignee")
+	}
+}
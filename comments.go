@@ -18,17 +18,86 @@ package tdapi
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/url"
-	"strconv"
 )
 
-func (c *TodoistClient) GetTaskComments(id int64) (response []Comment, err error) {
+// A CreateCommentRequest contains the fields to create a comment.
+// Exactly one of TaskID or ProjectID must be set.
+// See https://developer.todoist.com/rest/v2/?shell#create-a-new-comment
+type CreateCommentRequest struct {
+	TaskID     string      `json:"task_id,omitempty"`
+	ProjectID  string      `json:"project_id,omitempty"`
+	Content    string      `json:"content"`
+	Attachment *Attachment `json:"attachment,omitempty"`
+}
+
+// getComments returns all comments matching the query.
+func (c *TodoistClient) getComments(query url.Values) (response []Comment, err error) {
 	var body []byte
 
+	body, err = c.Get("/comments", query)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, &response)
+
+	return response, err
+}
+
+// GetTaskComments returns all comments for the task with id.
+func (c *TodoistClient) GetTaskComments(id string) (response []Comment, err error) {
+	if id == "" {
+		return response, fmt.Errorf("empty task ID")
+	}
+
 	query := url.Values{}
-	query.Set("task_id", strconv.FormatInt(id, 10))
+	query.Set("task_id", id)
 
-	body, err = c.Get("/comments", query)
+	return c.getComments(query)
+}
+
+// GetProjectComments returns all comments for the project with id.
+func (c *TodoistClient) GetProjectComments(id string) (response []Comment, err error) {
+	if id == "" {
+		return response, fmt.Errorf("empty project ID")
+	}
+
+	query := url.Values{}
+	query.Set("project_id", id)
+
+	return c.getComments(query)
+}
+
+// GetComment returns a comment by ID.
+func (c *TodoistClient) GetComment(id string) (response Comment, err error) {
+	var body []byte
+
+	if id == "" {
+		return response, fmt.Errorf("empty comment ID")
+	}
+
+	body, err = c.Get("/comments/"+id, nil)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, &response)
+
+	return response, err
+}
+
+// CreateComment creates a new comment on a task or project, returning
+// the created comment.
+func (c *TodoistClient) CreateComment(r CreateCommentRequest) (response Comment, err error) {
+	var body []byte
+
+	if (r.TaskID == "") == (r.ProjectID == "") {
+		return response, fmt.Errorf("comment must set exactly one of task or project ID")
+	}
+
+	body, err = c.postJSON("/comments", r)
 	if err != nil {
 		return response, err
 	}
@@ -37,3 +106,33 @@ func (c *TodoistClient) GetTaskComments(id int64) (response []Comment, err error
 
 	return response, err
 }
+
+// UpdateComment updates the content of a comment by ID, returning the
+// updated comment.
+func (c *TodoistClient) UpdateComment(id string, content string) (response Comment, err error) {
+	var body []byte
+
+	if id == "" {
+		return response, fmt.Errorf("empty comment ID")
+	}
+
+	body, err = c.postJSON("/comments/"+id, map[string]string{"content": content})
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, &response)
+
+	return response, err
+}
+
+// DeleteComment deletes a comment by ID.
+func (c *TodoistClient) DeleteComment(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty comment ID")
+	}
+
+	_, err := c.Delete("/comments/"+id, nil)
+
+	return err
+}
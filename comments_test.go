@@ -0,0 +1,164 @@
+package tdapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetTaskComments(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/v2/comments" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("task_id"); got != "2995104339" {
+			t.Errorf("task_id = %q, want %q", got, "2995104339")
+		}
+		http.ServeFile(w, r, "testdata/comments.json")
+	}))
+
+	comments, err := api.GetTaskComments("2995104339")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedComments := []Comment{
+		{
+			ID:       "2992679862",
+			TaskID:   "2995104339",
+			PostedAt: time.Date(2016, 9, 22, 7, 0, 0, 0, time.UTC),
+			Content:  "Need one bottle of milk",
+			Attachment: &Attachment{
+				ResourceType: "file",
+				FileName:     "File.pdf",
+				FileType:     "application/pdf",
+				FileURL:      "https://s3.amazonaws.com/domorebetter/Todoist+Setup+Guide.pdf",
+			},
+		},
+		{
+			ID:       "2992679863",
+			TaskID:   "2995104339",
+			PostedAt: time.Date(2016, 9, 22, 8, 0, 0, 0, time.UTC),
+			Content:  "Done",
+		},
+	}
+
+	if !reflect.DeepEqual(comments, expectedComments) {
+		t.Errorf("Comments do not match.\n Got: %+v\nWant: %+v\n", comments, expectedComments)
+	}
+}
+
+func TestCreateCommentTarget(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s", r.URL.Path)
+	}))
+
+	requests := []CreateCommentRequest{
+		{Content: "no target"},
+		{Content: "two targets", TaskID: "1", ProjectID: "2"},
+	}
+
+	for _, r := range requests {
+		if _, err := api.CreateComment(r); err == nil {
+			t.Errorf("CreateComment(%q) returned no error", r.Content)
+		}
+	}
+}
+
+func TestGetProjectComments(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/rest/v2/comments" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("project_id"); got != "2203306141" {
+			t.Errorf("project_id = %q, want %q", got, "2203306141")
+		}
+		if got := r.URL.Query().Get("task_id"); got != "" {
+			t.Errorf("task_id = %q, want none", got)
+		}
+		w.Write([]byte(`[{"id":"2992679864","project_id":"2203306141","posted_at":"2016-09-22T07:00:00Z","content":"Kickoff notes"}]`))
+	}))
+
+	comments, err := api.GetProjectComments("2203306141")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []Comment{{
+		ID:        "2992679864",
+		ProjectID: "2203306141",
+		PostedAt:  time.Date(2016, 9, 22, 7, 0, 0, 0, time.UTC),
+		Content:   "Kickoff notes",
+	}}
+	if !reflect.DeepEqual(comments, want) {
+		t.Errorf("Comments do not match.\n Got: %+v\nWant: %+v\n", comments, want)
+	}
+
+	if _, err := api.GetProjectComments(""); err == nil {
+		t.Errorf("GetProjectComments(\"\") did not return an error")
+	}
+}
+
+func TestGetComment(t *testing.T) {
+	api := newServerClient(t, requestHandler(t, http.MethodGet, "/rest/v2/comments/2992679862", nil,
+		http.StatusOK, `{"id":"2992679862","task_id":"2995104339","posted_at":"2016-09-22T07:00:00Z","content":"Need one bottle of milk","attachment":{"resource_type":"file","file_name":"File.pdf"}}`))
+
+	got, err := api.GetComment("2992679862")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := Comment{
+		ID:         "2992679862",
+		TaskID:     "2995104339",
+		PostedAt:   time.Date(2016, 9, 22, 7, 0, 0, 0, time.UTC),
+		Content:    "Need one bottle of milk",
+		Attachment: &Attachment{ResourceType: "file", FileName: "File.pdf"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Comment does not match.\n Got: %+v\nWant: %+v", got, want)
+	}
+
+	if _, err := api.GetComment(""); err == nil {
+		t.Errorf("GetComment(\"\") did not return an error")
+	}
+}
+
+func TestUpdateComment(t *testing.T) {
+	api := newServerClient(t, requestHandler(t, http.MethodPost, "/rest/v2/comments/2992679862",
+		map[string]interface{}{"content": "Need two bottles of milk"},
+		http.StatusOK, `{"id":"2992679862","task_id":"2995104339","posted_at":"2016-09-22T07:00:00Z","content":"Need two bottles of milk"}`))
+
+	got, err := api.UpdateComment("2992679862", "Need two bottles of milk")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := Comment{
+		ID:       "2992679862",
+		TaskID:   "2995104339",
+		PostedAt: time.Date(2016, 9, 22, 7, 0, 0, 0, time.UTC),
+		Content:  "Need two bottles of milk",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Comment does not match.\n Got: %+v\nWant: %+v", got, want)
+	}
+
+	if _, err := api.UpdateComment("", "content"); err == nil {
+		t.Errorf("UpdateComment(\"\") did not return an error")
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	api := newServerClient(t, requestHandler(t, http.MethodDelete, "/rest/v2/comments/2992679862", nil,
+		http.StatusNoContent, ""))
+
+	if err := api.DeleteComment("2992679862"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if err := api.DeleteComment(""); err == nil {
+		t.Errorf("DeleteComment(\"\") did not return an error")
+	}
+}
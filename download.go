@@ -0,0 +1,439 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// ManifestFileName is the name of the manifest written by MirrorAttachments.
+	ManifestFileName = "manifest.json"
+
+	// DefaultMirrorConcurrency is the default number of concurrent downloads.
+	DefaultMirrorConcurrency = 4
+)
+
+// isTodoistURL reports whether rawURL is hosted by Todoist, and so
+// requires the client's authorization to download.
+func isTodoistURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	return host == "todoist.com" ||
+		strings.HasSuffix(host, ".todoist.com") ||
+		strings.HasSuffix(host, ".todoist.net")
+}
+
+// DownloadAttachment writes the file of the attachment to w, returning
+// the number of bytes written. The client's authorization is only sent for
+// files hosted by Todoist, not for files on third party sites.
+func (c *TodoistClient) DownloadAttachment(ctx context.Context, a *Attachment, w io.Writer) (int64, error) {
+	if a == nil || a.FileURL == "" {
+		return 0, fmt.Errorf("attachment has no file URL")
+	}
+
+	resp, err := c.getFile(ctx, a.FileURL, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return io.Copy(w, resp.Body)
+}
+
+// getFile requests the file at rawURL starting at offset, returning the
+// response if successful. The client's authorization is only sent for files
+// hosted by Todoist. The caller must close the response body.
+func (c *TodoistClient) getFile(ctx context.Context, rawURL string, offset int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	client := http.DefaultClient
+	if isTodoistURL(rawURL) {
+		client = c.httpClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if codeIsError(resp.StatusCode) {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, &APIErrorResponse{Err: string(body), StatusCode: resp.StatusCode}
+	}
+
+	return resp, nil
+}
+
+// A ManifestEntry records a mirrored attachment.
+type ManifestEntry struct {
+	ProjectID string `json:"project_id,omitempty"`
+	TaskID    string `json:"task_id,omitempty"`
+	CommentID string `json:"comment_id"`
+	FileURL   string `json:"file_url"`
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256"`
+}
+
+// A Manifest records the mirrored attachments by path, relative to the
+// mirror directory and using forward slashes.
+type Manifest map[string]ManifestEntry
+
+// MirrorOptions are the optional parameters for MirrorAttachments.
+type MirrorOptions struct {
+	// Concurrency is the maximum number of concurrent downloads.
+	// If zero, DefaultMirrorConcurrency is used.
+	Concurrency int
+
+	// ProjectIDByTaskID maps task IDs to project IDs, so attachments of
+	// task comments are stored under the project of the task.
+	ProjectIDByTaskID map[string]string
+}
+
+// A MirrorResult reports the result of MirrorAttachments.
+type MirrorResult struct {
+	// Manifest records all mirrored attachments, including those from
+	// previous runs whose files are still in the mirror directory.
+	Manifest Manifest
+
+	// Downloaded and Skipped are the paths of attachments that were
+	// downloaded or skipped because they were unchanged.
+	Downloaded []string
+	Skipped    []string
+
+	// Failed are the errors for the paths of attachments that could not
+	// be downloaded.
+	Failed map[string]error
+}
+
+// safeName returns name as a single path element.
+func safeName(name string, defaultName string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+
+	if name == "" || name == "." || name == ".." {
+		return defaultName
+	}
+
+	return name
+}
+
+// AttachmentPath returns the path of the attachment of comment, relative
+// to the mirror directory, in the form project/task/comment/file. Project
+// comments use "project" for the task, and comments with an unknown project
+// use "unknown" for the project.
+func AttachmentPath(comment Comment, projectID string) string {
+	if projectID == "" {
+		projectID = comment.ProjectID
+	}
+
+	fileName := ""
+	if comment.Attachment != nil {
+		fileName = comment.Attachment.FileName
+	}
+
+	return path.Join(
+		safeName(projectID, "unknown"),
+		safeName(comment.TaskID, "project"),
+		safeName(comment.ID, "comment"),
+		safeName(fileName, "attachment"),
+	)
+}
+
+// readManifest reads the manifest in dir, returning an empty manifest if
+// it does not exist.
+func readManifest(dir string) (Manifest, error) {
+	manifest := make(Manifest)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, ManifestFileName))
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// writeManifest atomically replaces the manifest in dir.
+func writeManifest(dir string, manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(data, filepath.Join(dir, ManifestFileName))
+}
+
+// fileSHA256 returns the hex encoded SHA-256 of the file at name.
+func fileSHA256(name string) (string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// isUnchanged reports whether the file at name matches the manifest entry
+// for the same file URL.
+func isUnchanged(name string, entry ManifestEntry, fileURL string) bool {
+	if entry.FileURL != fileURL {
+		return false
+	}
+
+	sum, err := fileSHA256(name)
+	return err == nil && sum == entry.SHA256
+}
+
+// downloadToFile downloads the attachment to name, returning the size and
+// hex encoded SHA-256. The file is replaced only if the download succeeds.
+func (c *TodoistClient) downloadToFile(ctx context.Context, a *Attachment, name string) (int64, string, error) {
+	err := os.MkdirAll(filepath.Dir(name), 0o755)
+	if err != nil {
+		return 0, "", err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(name), ".download-*")
+	if err != nil {
+		return 0, "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := c.DownloadAttachment(ctx, a, io.MultiWriter(tmp, hash))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, "", err
+	}
+
+	err = os.Rename(tmp.Name(), name)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// MirrorAttachments downloads the file attachments of comments into dir,
+// using the layout of AttachmentPath, and records them in a manifest file
+// named ManifestFileName with the SHA-256 of each file.
+//
+// Files that match the manifest from a previous run are skipped. Failed
+// downloads are reported in the result and do not stop other downloads.
+// An error is returned only if the manifest cannot be read or written.
+func (c *TodoistClient) MirrorAttachments(ctx context.Context, dir string, comments []Comment, opts *MirrorOptions) (*MirrorResult, error) {
+	concurrency := DefaultMirrorConcurrency
+	var projectIDByTaskID map[string]string
+	if opts != nil {
+		if opts.Concurrency > 0 {
+			concurrency = opts.Concurrency
+		}
+		projectIDByTaskID = opts.ProjectIDByTaskID
+	}
+
+	previous, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep the entries of previous runs whose files are still there, such
+	// as attachments of tasks completed since
+	result := &MirrorResult{
+		Manifest: make(Manifest, len(previous)),
+		Failed:   make(map[string]error),
+	}
+	for relPath, entry := range previous {
+		_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(relPath)))
+		if err == nil {
+			result.Manifest[relPath] = entry
+		}
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+
+	for _, comment := range comments {
+		if comment.Attachment == nil || comment.Attachment.FileURL == "" {
+			continue
+		}
+
+		comment := comment
+		relPath := AttachmentPath(comment, projectIDByTaskID[comment.TaskID])
+		name := filepath.Join(dir, filepath.FromSlash(relPath))
+
+		entry := ManifestEntry{
+			ProjectID: comment.ProjectID,
+			TaskID:    comment.TaskID,
+			CommentID: comment.ID,
+			FileURL:   comment.Attachment.FileURL,
+		}
+		if entry.ProjectID == "" {
+			entry.ProjectID = projectIDByTaskID[comment.TaskID]
+		}
+
+		// wait for a free slot so at most concurrency goroutines exist
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			prev, ok := previous[relPath]
+			if ok && isUnchanged(name, prev, entry.FileURL) {
+				mu.Lock()
+				result.Skipped = append(result.Skipped, relPath)
+				mu.Unlock()
+				return
+			}
+
+			size, sum, err := c.downloadToFile(ctx, comment.Attachment, name)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				// the previous file and its entry, if any, are kept
+				result.Failed[relPath] = err
+				return
+			}
+
+			entry.Size = size
+			entry.SHA256 = sum
+			result.Manifest[relPath] = entry
+			result.Downloaded = append(result.Downloaded, relPath)
+		}()
+	}
+
+	wg.Wait()
+
+	sort.Strings(result.Downloaded)
+	sort.Strings(result.Skipped)
+
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return result, err
+	}
+
+	return result, writeManifest(dir, result.Manifest)
+}
+
+// MirrorAllAttachments downloads the file attachments of the comments on
+// all projects and active tasks into dir. See MirrorAttachments.
+// The projects of active tasks are added to opts.ProjectIDByTaskID,
+// which is not modified.
+func (c *TodoistClient) MirrorAllAttachments(ctx context.Context, dir string, opts *MirrorOptions) (*MirrorResult, error) {
+	projects, err := c.GetAllProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := c.GetActiveTasks(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []Comment
+	for _, project := range projects {
+		if project.CommentCount == 0 {
+			continue
+		}
+
+		projectComments, err := c.GetProjectComments(project.ID)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, projectComments...)
+	}
+
+	// add the projects of active tasks to those given by the caller, which
+	// may include tasks that are no longer active
+	projectIDByTaskID := make(map[string]string, len(tasks))
+	if opts != nil {
+		for taskID, projectID := range opts.ProjectIDByTaskID {
+			projectIDByTaskID[taskID] = projectID
+		}
+	}
+	for _, task := range tasks {
+		projectIDByTaskID[task.ID] = task.ProjectID
+		if task.CommentCount == 0 {
+			continue
+		}
+
+		taskComments, err := c.GetTaskComments(task.ID)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, taskComments...)
+	}
+
+	mirrorOpts := MirrorOptions{}
+	if opts != nil {
+		mirrorOpts = *opts
+	}
+	mirrorOpts.ProjectIDByTaskID = projectIDByTaskID
+
+	return c.MirrorAttachments(ctx, dir, comments, &mirrorOpts)
+}
@@ -0,0 +1,124 @@
+package tdapi
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestMirrorAttachments(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests = make(map[string]int)
+		authHost = make(map[string]bool)
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		// only requests sent with the client have a forwarded host
+		authHost[r.URL.Path] = r.Header.Get("X-Forwarded-Host") != ""
+		mu.Unlock()
+
+		if r.URL.Path == "/missing.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, "content of "+r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+
+	api := serverClient(t, server)
+
+	comments := []Comment{
+		{ID: "c1", TaskID: "t1", Attachment: &Attachment{FileName: "log.txt", FileURL: "https://files.todoist.com/log.txt"}},
+		{ID: "c2", ProjectID: "p2", Attachment: &Attachment{FileName: "../spec.pdf", FileURL: server.URL + "/spec.pdf"}},
+		{ID: "c3", TaskID: "t1", Attachment: &Attachment{FileName: "gone.txt", FileURL: server.URL + "/missing.txt"}},
+		{ID: "c4", TaskID: "t1", Attachment: &Attachment{ResourceType: "url", URL: "https://example.com"}},
+		{ID: "c5", TaskID: "t1"},
+	}
+	opts := &MirrorOptions{Concurrency: 2, ProjectIDByTaskID: map[string]string{"t1": "p1"}}
+
+	dir := t.TempDir()
+	result, err := api.MirrorAttachments(context.Background(), dir, comments, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	wantDownloaded := []string{"p1/t1/c1/log.txt", "p2/project/c2/.._spec.pdf"}
+	if !reflect.DeepEqual(result.Downloaded, wantDownloaded) {
+		t.Errorf("Downloaded = %v, want %v", result.Downloaded, wantDownloaded)
+	}
+	if _, ok := result.Failed["p1/t1/c3/gone.txt"]; !ok || len(result.Failed) != 1 {
+		t.Errorf("Failed = %v, want p1/t1/c3/gone.txt", result.Failed)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "p1", "t1", "c1", "log.txt"))
+	if err != nil || string(data) != "content of /log.txt" {
+		t.Errorf("File content = %q, %v", data, err)
+	}
+
+	entry := result.Manifest["p1/t1/c1/log.txt"]
+	if entry.ProjectID != "p1" || entry.Size != int64(len("content of /log.txt")) || len(entry.SHA256) != 64 {
+		t.Errorf("Unexpected manifest entry: %+v", entry)
+	}
+
+	if !authHost["/log.txt"] || authHost["/spec.pdf"] {
+		t.Errorf("Client auth used = %v, want only for Todoist URLs", authHost)
+	}
+
+	// a second run skips the unchanged files
+	result, err = api.MirrorAttachments(context.Background(), dir, comments, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Skipped, wantDownloaded) || len(result.Downloaded) != 0 {
+		t.Errorf("Skipped = %v, Downloaded = %v, want all skipped", result.Skipped, result.Downloaded)
+	}
+	if requests["/log.txt"] != 1 || requests["/spec.pdf"] != 1 {
+		t.Errorf("Requests = %v, want one per file", requests)
+	}
+
+	manifest, err := readManifest(dir)
+	if err != nil || !reflect.DeepEqual(manifest, result.Manifest) {
+		t.Errorf("Manifest file does not match result: %v", err)
+	}
+
+	// a run without the comment of a completed task keeps its entry
+	result, err = api.MirrorAttachments(context.Background(), dir, comments[1:], opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := result.Manifest["p1/t1/c1/log.txt"]; !ok {
+		t.Errorf("Manifest dropped the entry of an earlier run: %v", result.Manifest)
+	}
+
+	// an entry is dropped once its file is removed
+	os.Remove(filepath.Join(dir, "p1", "t1", "c1", "log.txt"))
+	result, err = api.MirrorAttachments(context.Background(), dir, comments[1:], opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := result.Manifest["p1/t1/c1/log.txt"]; ok || len(result.Manifest) != 1 {
+		t.Errorf("Manifest = %v, want only p2/project/c2/.._spec.pdf", result.Manifest)
+	}
+	// the manifest is replaced without leaving temporary files behind
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	if want := []string{ManifestFileName, "p1", "p2"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Directory contains %v, want %v", names, want)
+	}
+}
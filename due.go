@@ -0,0 +1,374 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// dueDateLayout is the layout of a due date without a time.
+	dueDateLayout = "2006-01-02"
+
+	// dueFloatingLayout is the layout of a due date and time without a
+	// timezone. Fractional seconds are accepted when parsing.
+	dueFloatingLayout = "2006-01-02T15:04:05"
+)
+
+// DueKind describes how the date and time of a TaskDue is interpreted.
+type DueKind int
+
+const (
+	// DueDateOnly is a full-day due date without a time.
+	DueDateOnly DueKind = iota
+
+	// DueFloating is a due date and time that follows the local timezone,
+	// e.g., 9am is always 9am wherever the user is.
+	DueFloating
+
+	// DueFixed is a due date and time in a fixed timezone, e.g., 9am in
+	// New York is 3pm in Paris.
+	DueFixed
+)
+
+// String returns a string representation of the DueKind.
+func (k DueKind) String() string {
+	switch k {
+	case DueDateOnly:
+		return "date"
+	case DueFloating:
+		return "floating"
+	case DueFixed:
+		return "fixed"
+	}
+	return fmt.Sprintf("DueKind(%d)", int(k))
+}
+
+// DueBucket groups due dates relative to the current day.
+type DueBucket int
+
+const (
+	BucketNone DueBucket = iota
+	BucketOverdue
+	BucketToday
+	BucketTomorrow
+	BucketThisWeek
+	BucketLater
+)
+
+// String returns a string representation of the DueBucket.
+func (b DueBucket) String() string {
+	switch b {
+	case BucketNone:
+		return "none"
+	case BucketOverdue:
+		return "overdue"
+	case BucketToday:
+		return "today"
+	case BucketTomorrow:
+		return "tomorrow"
+	case BucketThisWeek:
+		return "this week"
+	case BucketLater:
+		return "later"
+	}
+	return fmt.Sprintf("DueBucket(%d)", int(b))
+}
+
+// A TaskDue represents the due date of a task.
+//
+// Date is always set. Datetime is only set when the due date has a time
+// and Timezone is only set when that time is in a fixed timezone.
+// The Sync API returns the time as part of Date, which is also handled.
+//
+// See https://developer.todoist.com/rest/v2/?shell#tasks
+type TaskDue struct {
+	String      string `json:"string"`
+	Date        string `json:"date"`
+	IsRecurring bool   `json:"is_recurring"`
+	Datetime    string `json:"datetime,omitempty"`
+	Timezone    string `json:"timezone,omitempty"`
+	Lang        string `json:"lang,omitempty"`
+}
+
+// A DueRequest is a due date sent in a Sync API command, such as the due
+// date of an item_add command or of an absolute reminder. Unlike TaskDue,
+// only the fields that are set are sent.
+type DueRequest struct {
+	// Date is the date, or the date and time, e.g., "2024-03-10",
+	// "2024-03-10T13:00:00Z" for a time in UTC, or "2024-03-10T09:00:00"
+	// for a floating time.
+	Date     string `json:"date,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+
+	// String is the due date in natural language, such as "tomorrow 9am"
+	// or "every monday", interpreted using Lang.
+	String string `json:"string,omitempty"`
+	Lang   string `json:"lang,omitempty"`
+}
+
+// datetime returns the date and time string, or empty if there is no time.
+func (d TaskDue) datetime() string {
+	if d.Datetime != "" {
+		return d.Datetime
+	}
+	if strings.Contains(d.Date, "T") {
+		return d.Date
+	}
+	return ""
+}
+
+// Kind returns how the due date is interpreted.
+func (d TaskDue) Kind() DueKind {
+	switch {
+	case d.datetime() == "":
+		return DueDateOnly
+	case d.Timezone != "":
+		return DueFixed
+	}
+	return DueFloating
+}
+
+// hasZone reports whether the datetime string s includes a UTC offset.
+func hasZone(s string) bool {
+	if strings.HasSuffix(s, "Z") {
+		return true
+	}
+
+	// offset such as +05:00 or -05:00 after the time
+	i := strings.LastIndexAny(s, "+-")
+	return i > strings.Index(s, "T")
+}
+
+// Time returns the due time in loc. A nil loc uses time.Local.
+//
+// A date only due date returns midnight at the start of the day in loc.
+// A floating due date returns the wall clock time in loc.
+// A fixed due date returns the absolute time converted to loc.
+func (d TaskDue) Time(loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	s := d.datetime()
+	if s == "" {
+		return parseWallClock(dueDateLayout, d.Date, loc)
+	}
+
+	if hasZone(s) {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return t.In(loc), nil
+	}
+
+	if d.Timezone != "" {
+		tz, err := time.LoadLocation(d.Timezone)
+		if err != nil {
+			return time.Time{}, err
+		}
+		t, err := parseWallClock(dueFloatingLayout, s, tz)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return t.In(loc), nil
+	}
+
+	return parseWallClock(dueFloatingLayout, s, loc)
+}
+
+// parseWallClock parses the wall clock time s in loc. A wall clock time
+// skipped by a daylight saving time transition is moved forward by the
+// length of the transition, e.g., 2:30am becomes 3:30am.
+func parseWallClock(layout string, s string, loc *time.Location) (time.Time, error) {
+	wall, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(),
+		wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), loc)
+
+	// compare the requested wall clock with the resulting wall clock
+	got := time.Date(t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	if gap := wall.Sub(got); gap > 0 {
+		t = t.Add(gap)
+	}
+
+	return t, nil
+}
+
+// deadline returns the time in loc after which the due date is overdue.
+// For a date only due date, this is the start of the following day.
+func (d TaskDue) deadline(loc *time.Location) (time.Time, error) {
+	t, err := d.Time(loc)
+	if err != nil {
+		return t, err
+	}
+
+	if d.Kind() == DueDateOnly {
+		t = addDays(t, 1)
+	}
+
+	return t, nil
+}
+
+// addDays returns midnight n calendar days after the day of t, which is
+// not always n*24 hours because of daylight saving time.
+func addDays(t time.Time, n int) time.Time {
+	y, m, day := t.Date()
+	return time.Date(y, m, day+n, 0, 0, 0, 0, t.Location())
+}
+
+// IsOverdue reports whether the due date has passed at now, using the
+// location of now for date only and floating due dates. A date only due
+// date is overdue once its day has ended. IsOverdue returns false if the
+// due date cannot be parsed.
+func (d TaskDue) IsOverdue(now time.Time) bool {
+	deadline, err := d.deadline(now.Location())
+	if err != nil {
+		return false
+	}
+
+	return !now.Before(deadline)
+}
+
+// DueWithin reports whether the due date is not overdue at now and falls
+// due within the duration dur after now. A date only due date falls due at
+// the end of its day. DueWithin returns false if the due date cannot be
+// parsed.
+func (d TaskDue) DueWithin(now time.Time, dur time.Duration) bool {
+	deadline, err := d.deadline(now.Location())
+	if err != nil {
+		return false
+	}
+
+	return now.Before(deadline) && !deadline.After(now.Add(dur))
+}
+
+// DayBucket returns the bucket of the due date relative to the day of now,
+// using the location of now and weeks starting on Monday.
+func (d TaskDue) DayBucket(now time.Time) DueBucket {
+	return d.dayBucket(now, time.Monday)
+}
+
+// DayBucketFor returns the bucket of the due date relative to the day of
+// now, using the timezone and the first day of the week of u. A nil u uses
+// the location of now and weeks starting on Monday.
+func (d TaskDue) DayBucketFor(now time.Time, u *User) DueBucket {
+	if u == nil {
+		return d.dayBucket(now, time.Monday)
+	}
+
+	return d.dayBucket(now.In(u.Location()), u.WeekStart())
+}
+
+// IsOverdueFor reports whether the due date has passed at now, using the
+// timezone of u for date only and floating due dates. A nil u uses the
+// location of now.
+func (d TaskDue) IsOverdueFor(now time.Time, u *User) bool {
+	if u != nil {
+		now = now.In(u.Location())
+	}
+
+	return d.IsOverdue(now)
+}
+
+// dayBucket returns the bucket of the due date relative to the day of now,
+// using the location of now and weeks starting on weekStart.
+func (d TaskDue) dayBucket(now time.Time, weekStart time.Weekday) DueBucket {
+	t, err := d.Time(now.Location())
+	if err != nil {
+		return BucketNone
+	}
+
+	if d.IsOverdue(now) {
+		return BucketOverdue
+	}
+
+	today := addDays(now, 0)
+	due := addDays(t, 0)
+
+	// days until the start of the next week, at least one
+	daysLeft := (int(weekStart) - int(today.Weekday()) + 7) % 7
+	if daysLeft == 0 {
+		daysLeft = 7
+	}
+
+	switch {
+	case due.Equal(today):
+		return BucketToday
+	case due.Equal(addDays(today, 1)):
+		return BucketTomorrow
+	case due.Before(addDays(today, daysLeft)):
+		return BucketThisWeek
+	}
+
+	return BucketLater
+}
+
+// A TaskDeadline represents the deadline of a task, which is a date
+// without a time. It has the same date handling as a date only TaskDue.
+//
+// See https://developer.todoist.com/rest/v2/?shell#tasks
+type TaskDeadline struct {
+	Date string `json:"date"`
+	Lang string `json:"lang,omitempty"`
+}
+
+// due returns the deadline as a date only TaskDue.
+func (d TaskDeadline) due() TaskDue {
+	return TaskDue{Date: d.Date, Lang: d.Lang}
+}
+
+// Time returns midnight at the start of the deadline in loc.
+// A nil loc uses time.Local.
+func (d TaskDeadline) Time(loc *time.Location) (time.Time, error) {
+	return d.due().Time(loc)
+}
+
+// IsOverdue reports whether the day of the deadline has ended at now.
+func (d TaskDeadline) IsOverdue(now time.Time) bool {
+	return d.due().IsOverdue(now)
+}
+
+// DueWithin reports whether the deadline has not passed at now and ends
+// within the duration dur after now.
+func (d TaskDeadline) DueWithin(now time.Time, dur time.Duration) bool {
+	return d.due().DueWithin(now, dur)
+}
+
+// DayBucket returns the bucket of the deadline relative to the day of now.
+func (d TaskDeadline) DayBucket(now time.Time) DueBucket {
+	return d.due().dayBucket(now, time.Monday)
+}
+
+// DayBucketFor returns the bucket of the deadline relative to the day of
+// now, using the timezone and the first day of the week of u.
+func (d TaskDeadline) DayBucketFor(now time.Time, u *User) DueBucket {
+	return d.due().DayBucketFor(now, u)
+}
+
+// IsOverdueFor reports whether the day of the deadline has ended at now in
+// the timezone of u.
+func (d TaskDeadline) IsOverdueFor(now time.Time, u *User) bool {
+	return d.due().IsOverdueFor(now, u)
+}
@@ -0,0 +1,235 @@
+package tdapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("Cannot load location %q: %v", name, err)
+	}
+
+	return loc
+}
+
+func TestTaskDueDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want DueKind
+	}{
+		{"date", `{"date":"2023-03-12","string":"Mar 12"}`, DueDateOnly},
+		{"floating", `{"date":"2023-03-12","datetime":"2023-03-12T09:00:00.000000"}`, DueFloating},
+		{"fixed", `{"date":"2023-03-12","datetime":"2023-03-12T13:00:00.000000Z","timezone":"America/New_York"}`, DueFixed},
+		{"sync floating", `{"date":"2023-03-12T09:00:00"}`, DueFloating},
+		{"sync fixed", `{"date":"2023-03-12T13:00:00Z","timezone":"America/New_York"}`, DueFixed},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var due TaskDue
+			if err := json.Unmarshal([]byte(tc.json), &due); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := due.Kind(); got != tc.want {
+				t.Errorf("Kind() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTaskDueTime(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	paris := mustLoadLocation(t, "Europe/Paris")
+
+	tests := []struct {
+		name string
+		due  TaskDue
+		loc  *time.Location
+		want time.Time
+	}{
+		{
+			"date only",
+			TaskDue{Date: "2023-03-12"},
+			ny,
+			time.Date(2023, 3, 12, 0, 0, 0, 0, ny),
+		},
+		{
+			// 2:30am does not exist in New York on this day
+			"floating in spring forward gap",
+			TaskDue{Date: "2023-03-12", Datetime: "2023-03-12T02:30:00"},
+			ny,
+			time.Date(2023, 3, 12, 3, 30, 0, 0, ny),
+		},
+		{
+			"floating follows location",
+			TaskDue{Date: "2023-03-12", Datetime: "2023-03-12T09:00:00"},
+			paris,
+			time.Date(2023, 3, 12, 9, 0, 0, 0, paris),
+		},
+		{
+			"fixed converts to location",
+			TaskDue{Date: "2023-03-12", Datetime: "2023-03-12T13:00:00Z", Timezone: "America/New_York"},
+			paris,
+			time.Date(2023, 3, 12, 14, 0, 0, 0, paris),
+		},
+		{
+			// 1:30am happens twice in New York on this day
+			"fixed in fall back overlap",
+			TaskDue{Date: "2023-11-05", Datetime: "2023-11-05T06:30:00Z", Timezone: "America/New_York"},
+			ny,
+			time.Date(2023, 11, 5, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			"fixed without offset uses timezone",
+			TaskDue{Date: "2023-11-05T09:00:00", Timezone: "Europe/Paris"},
+			ny,
+			time.Date(2023, 11, 5, 9, 0, 0, 0, paris),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.due.Time(tc.loc)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("Time() = %v, want %v", got, tc.want)
+			}
+			if got.Location() != tc.loc {
+				t.Errorf("Location() = %v, want %v", got.Location(), tc.loc)
+			}
+		})
+	}
+}
+
+func TestTaskDueIsOverdue(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name string
+		due  TaskDue
+		now  time.Time
+		want bool
+	}{
+		{"date today", TaskDue{Date: "2023-03-12"}, time.Date(2023, 3, 12, 23, 59, 0, 0, ny), false},
+		{"date yesterday", TaskDue{Date: "2023-03-11"}, time.Date(2023, 3, 12, 0, 0, 0, 0, ny), true},
+		{"floating before", TaskDue{Date: "2023-03-12T09:00:00"}, time.Date(2023, 3, 12, 8, 59, 0, 0, ny), false},
+		{"floating at", TaskDue{Date: "2023-03-12T09:00:00"}, time.Date(2023, 3, 12, 9, 0, 0, 0, ny), true},
+		{
+			// 1:30am EDT is 5:30 UTC, the second 1:30am EST is 6:30 UTC
+			"fixed in fall back overlap",
+			TaskDue{Datetime: "2023-11-05T06:30:00Z", Timezone: "America/New_York"},
+			time.Date(2023, 11, 5, 5, 45, 0, 0, time.UTC).In(ny),
+			false,
+		},
+		{"unparsable", TaskDue{Date: "soon"}, time.Date(2023, 3, 12, 0, 0, 0, 0, ny), false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.due.IsOverdue(tc.now); got != tc.want {
+				t.Errorf("IsOverdue() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTaskDueDueWithin(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name string
+		due  TaskDue
+		now  time.Time
+		dur  time.Duration
+		want bool
+	}{
+		{
+			// only 23 hours in the day of the spring forward
+			"date across spring forward",
+			TaskDue{Date: "2023-03-12"},
+			time.Date(2023, 3, 12, 0, 0, 0, 0, ny),
+			23 * time.Hour,
+			true,
+		},
+		{
+			// 25 hours in the day of the fall back
+			"date across fall back",
+			TaskDue{Date: "2023-11-05"},
+			time.Date(2023, 11, 5, 0, 0, 0, 0, ny),
+			24 * time.Hour,
+			false,
+		},
+		{"fixed within", TaskDue{Datetime: "2023-03-12T13:00:00Z", Timezone: "UTC"}, time.Date(2023, 3, 12, 12, 0, 0, 0, time.UTC), time.Hour, true},
+		{"fixed beyond", TaskDue{Datetime: "2023-03-12T13:00:00Z", Timezone: "UTC"}, time.Date(2023, 3, 12, 11, 59, 0, 0, time.UTC), time.Hour, false},
+		{"overdue", TaskDue{Date: "2023-03-10"}, time.Date(2023, 3, 12, 0, 0, 0, 0, ny), 48 * time.Hour, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.due.DueWithin(tc.now, tc.dur); got != tc.want {
+				t.Errorf("DueWithin() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTaskDueDayBucket(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+
+	// Saturday, the day before the spring forward
+	saturday := time.Date(2023, 3, 11, 22, 0, 0, 0, ny)
+
+	// Saturday, the day before the fall back
+	fallSaturday := time.Date(2023, 11, 4, 22, 0, 0, 0, ny)
+
+	tests := []struct {
+		name string
+		due  TaskDue
+		now  time.Time
+		want DueBucket
+	}{
+		{"overdue", TaskDue{Date: "2023-03-10"}, saturday, BucketOverdue},
+		{"today", TaskDue{Date: "2023-03-11"}, saturday, BucketToday},
+		{"today earlier time", TaskDue{Date: "2023-03-11T21:00:00"}, saturday, BucketOverdue},
+		{"tomorrow across spring forward", TaskDue{Date: "2023-03-12T23:30:00"}, saturday, BucketTomorrow},
+		{"tomorrow across fall back", TaskDue{Date: "2023-11-05T23:30:00"}, fallSaturday, BucketTomorrow},
+		{"later next week", TaskDue{Date: "2023-03-13"}, saturday, BucketLater},
+		{"this week", TaskDue{Date: "2023-03-16"}, time.Date(2023, 3, 13, 9, 0, 0, 0, ny), BucketThisWeek},
+		{"this week ends sunday", TaskDue{Date: "2023-03-19"}, time.Date(2023, 3, 13, 9, 0, 0, 0, ny), BucketThisWeek},
+		{"fixed lands tomorrow locally", TaskDue{Datetime: "2023-03-12T04:30:00Z", Timezone: "Europe/Paris"}, time.Date(2023, 3, 11, 22, 0, 0, 0, time.UTC), BucketTomorrow},
+		{"unparsable", TaskDue{Date: "soon"}, saturday, BucketNone},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.due.DayBucket(tc.now); got != tc.want {
+				t.Errorf("DayBucket() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTaskDeadline(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+
+	deadline := TaskDeadline{Date: "2023-03-12"}
+	now := time.Date(2023, 3, 11, 22, 0, 0, 0, ny)
+
+	if deadline.IsOverdue(now) {
+		t.Errorf("IsOverdue() = true, want false")
+	}
+	if got := deadline.DayBucket(now); got != BucketTomorrow {
+		t.Errorf("DayBucket() = %v, want %v", got, BucketTomorrow)
+	}
+	if !deadline.DueWithin(now, 25*time.Hour) {
+		t.Errorf("DueWithin() = false, want true")
+	}
+}
@@ -19,6 +19,9 @@ package tdapi
 // APIErrorResponse contains a single property named error.
 type APIErrorResponse struct {
 	Err string
+
+	// StatusCode is the HTTP status code of the response, if known.
+	StatusCode int
 }
 
 // Error return a string representation of the error
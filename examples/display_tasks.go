@@ -123,43 +123,41 @@ func main() {
 		fmt.Println(projectByID[projectID].Name, len(tasks))
 	}
 
-	childProjectIDs := tdapi.ChildProjectIDs(projects)
-
-	var printRecursive func(projectID string, indent string)
-	printRecursive = func(projectID string, indent string) {
-		tasks := groupByProjectID[projectID]
-		if len(tasks) > 0 {
-			fmt.Println(
-				indent,
-				projectByID[projectID].Name,
-				len(tasks),
-			)
+	projectTree := tdapi.NewProjectTree(projects)
+	if err := projectTree.Validate(); err != nil {
+		fmt.Println("Warning:", err)
+	}
 
-			sort.Sort(TasksByPriorityThenOrder(tasks))
-
-			for _, task := range tasks {
-				var dueDate string
-				if task.Due != nil {
-					dueDate = task.Due.Date
-				}
-				fmt.Printf("%s %.*s %d %d %s\n",
-					indent+"  ",
-					20,
-					task.Content,
-					task.Priority,
-					task.Order,
-					dueDate,
-				)
-			}
+	fmt.Println("-----")
+	projectTree.Walk(func(project tdapi.Project, depth int) error {
+		indent := strings.Repeat("-", depth+1)
+
+		tasks := groupByProjectID[project.ID]
+		if len(tasks) == 0 {
+			return nil
 		}
 
-		for _, childID := range childProjectIDs[projectID] {
-			printRecursive(childID, indent+"-")
+		fmt.Println(indent, project.Name, len(tasks))
+
+		sort.Sort(TasksByPriorityThenOrder(tasks))
+
+		for _, task := range tasks {
+			var dueDate string
+			if task.Due != nil {
+				dueDate = task.Due.Date
+			}
+			fmt.Printf("%s %.*s %d %d %s\n",
+				indent+"  ",
+				20,
+				task.Content,
+				task.Priority,
+				task.Order,
+				dueDate,
+			)
 		}
-	}
 
-	fmt.Println("-----")
-	printRecursive("", "")
+		return nil
+	})
 }
 
 func prettyPrint(v interface{}) {
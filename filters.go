@@ -0,0 +1,227 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A Filter is a saved filter query.
+// See https://developer.todoist.com/sync/v9/#filters
+type Filter struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Query      string `json:"query"`
+	Color      string `json:"color"`
+	ItemOrder  int    `json:"item_order"`
+	IsFavorite bool   `json:"is_favorite"`
+	IsDeleted  bool   `json:"is_deleted"`
+}
+
+// A FilterRequest contains the fields to create or update a filter.
+// Nil fields are not sent, so they are not changed by an update.
+// See https://developer.todoist.com/sync/v9/#add-a-filter
+type FilterRequest struct {
+	Name       *string `json:"name,omitempty"`
+	Query      *string `json:"query,omitempty"`
+	Color      *string `json:"color,omitempty"`
+	ItemOrder  *int    `json:"item_order,omitempty"`
+	IsFavorite *bool   `json:"is_favorite,omitempty"`
+}
+
+// GetFilters returns all saved filters, sorted by ItemOrder.
+func (c *TodoistClient) GetFilters(ctx context.Context) ([]Filter, error) {
+	response, err := c.Sync(ctx, "", []string{ResourceFilters})
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make([]Filter, 0, len(response.Filters))
+	for _, filter := range response.Filters {
+		if !filter.IsDeleted {
+			filters = append(filters, filter)
+		}
+	}
+
+	sort.SliceStable(filters, func(i, j int) bool {
+		return filters[i].ItemOrder < filters[j].ItemOrder
+	})
+
+	return filters, nil
+}
+
+// CreateFilter creates a new filter, returning the created filter.
+// Name and Query are required.
+func (c *TodoistClient) CreateFilter(ctx context.Context, r FilterRequest) (Filter, error) {
+	if r.Name == nil || *r.Name == "" || r.Query == nil || *r.Query == "" {
+		return Filter{}, fmt.Errorf("filter name and query are required")
+	}
+
+	cmd := NewCommand("filter_add", r)
+	cmd.TempID = newUUID()
+
+	response, err := c.executeCommand(ctx, cmd)
+	if err != nil {
+		return Filter{}, err
+	}
+
+	filter := Filter{
+		ID:    response.TempIDMapping[cmd.TempID],
+		Name:  *r.Name,
+		Query: *r.Query,
+	}
+	if r.Color != nil {
+		filter.Color = *r.Color
+	}
+	if r.ItemOrder != nil {
+		filter.ItemOrder = *r.ItemOrder
+	}
+	if r.IsFavorite != nil {
+		filter.IsFavorite = *r.IsFavorite
+	}
+
+	return filter, nil
+}
+
+// UpdateFilter updates the filter with id.
+func (c *TodoistClient) UpdateFilter(ctx context.Context, id string, r FilterRequest) error {
+	if id == "" {
+		return fmt.Errorf("empty filter ID")
+	}
+
+	args := struct {
+		ID string `json:"id"`
+		FilterRequest
+	}{id, r}
+
+	_, err := c.executeCommand(ctx, NewCommand("filter_update", args))
+
+	return err
+}
+
+// DeleteFilter deletes the filter with id.
+func (c *TodoistClient) DeleteFilter(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("empty filter ID")
+	}
+
+	_, err := c.executeCommand(ctx,
+		NewCommand("filter_delete", map[string]string{"id": id}))
+
+	return err
+}
+
+// ReorderFilters sets the order of filters to the order of ids.
+func (c *TodoistClient) ReorderFilters(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("no filter IDs")
+	}
+
+	orders := make(map[string]int, len(ids))
+	for n, id := range ids {
+		orders[id] = n + 1
+	}
+
+	_, err := c.executeCommand(ctx, NewCommand("filter_update_orders",
+		map[string]interface{}{"id_order_mapping": orders}))
+
+	return err
+}
+
+// splitFilterQuery splits query into its comma separated queries. Commas
+// inside parentheses or escaped with a backslash do not separate queries.
+func splitFilterQuery(query string) []string {
+	var queries []string
+
+	depth, start := 0, 0
+	for i := 0; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				queries = append(queries, query[start:i])
+				start = i + 1
+			}
+		}
+	}
+	queries = append(queries, query[start:])
+
+	// drop empty queries, such as after a trailing comma
+	n := 0
+	for _, q := range queries {
+		if q = strings.TrimSpace(q); q != "" {
+			queries[n] = q
+			n++
+		}
+	}
+
+	return queries[:n]
+}
+
+// RunFilter returns the active tasks matching the query of the saved
+// filter with name, which is matched without regard to case.
+//
+// A query with several comma separated queries, such as "today, overdue",
+// is run one query at a time, since the REST API accepts only one. The
+// tasks are returned in the order of the queries, without duplicates.
+func (c *TodoistClient) RunFilter(ctx context.Context, name string) ([]Task, error) {
+	filters, err := c.GetFilters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, filter := range filters {
+		if !strings.EqualFold(filter.Name, name) {
+			continue
+		}
+
+		queries := splitFilterQuery(filter.Query)
+		if len(queries) == 0 {
+			return nil, fmt.Errorf("filter %q has an empty query", filter.Name)
+		}
+
+		var tasks []Task
+		seen := make(map[string]bool)
+		for _, query := range queries {
+			queryTasks, err := c.GetActiveTasks(&TaskParameters{Filter: query})
+			if err != nil {
+				return nil, fmt.Errorf("filter %q query %q: %w", filter.Name, query, err)
+			}
+
+			for _, task := range queryTasks {
+				if !seen[task.ID] {
+					seen[task.ID] = true
+					tasks = append(tasks, task)
+				}
+			}
+		}
+
+		return tasks, nil
+	}
+
+	return nil, fmt.Errorf("no filter named %q", name)
+}
@@ -0,0 +1,210 @@
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// filterServer serves a fixed set of filters, records received commands,
+// and answers active task requests with a single task.
+func filterServer(t *testing.T, commands *[]Command, queries *[]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sync/v9/sync":
+			if r.FormValue("commands") != "" {
+				var cmds []Command
+				if err := json.Unmarshal([]byte(r.FormValue("commands")), &cmds); err != nil {
+					t.Errorf("Cannot decode commands: %v", err)
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				*commands = append(*commands, cmds...)
+
+				status := make(map[string]string)
+				mapping := make(map[string]string)
+				for _, cmd := range cmds {
+					status[cmd.UUID] = "ok"
+					if cmd.TempID != "" {
+						mapping[cmd.TempID] = "4638878"
+					}
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"sync_status":     status,
+					"temp_id_mapping": mapping,
+				})
+				return
+			}
+
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"sync_token": "token",
+				"full_sync":  true,
+				"filters": []Filter{
+					{ID: "3", Name: "Old", Query: "p4", ItemOrder: 1, IsDeleted: true},
+					{ID: "2", Name: "Urgent", Query: "p1 & today", ItemOrder: 2},
+					{ID: "1", Name: "Work", Query: "#Work", ItemOrder: 1},
+					{ID: "4", Name: "Due", Query: "today, overdue,(p1 | p2) & #Work\\, Home", ItemOrder: 3},
+				},
+			})
+		case "/rest/v2/tasks":
+			query := r.URL.Query().Get("filter")
+			*queries = append(*queries, query)
+			switch query {
+			case "p1 & today", "today":
+				w.Write([]byte(`[{"id":"100","content":"Fix it"}]`))
+			case "overdue":
+				w.Write([]byte(`[{"id":"101","content":"Late"},{"id":"100","content":"Fix it"}]`))
+			case `(p1 | p2) & #Work\, Home`:
+				w.Write([]byte(`[{"id":"102","content":"Plan"}]`))
+			default:
+				// like the REST API, which rejects several queries
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	})
+}
+
+func TestGetFilters(t *testing.T) {
+	var commands []Command
+	var queries []string
+	api := newServerClient(t, filterServer(t, &commands, &queries))
+
+	filters, err := api.GetFilters(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, filter := range filters {
+		ids = append(ids, filter.ID)
+	}
+	if want := []string{"1", "2", "4"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Filter IDs = %v, want %v", ids, want)
+	}
+}
+
+func TestFilterCommands(t *testing.T) {
+	var commands []Command
+	var queries []string
+	api := newServerClient(t, filterServer(t, &commands, &queries))
+	ctx := context.Background()
+
+	filter, err := api.CreateFilter(ctx, FilterRequest{Name: stringPtr("Today"), Query: stringPtr("today")})
+	if err != nil {
+		t.Fatalf("CreateFilter: %v", err)
+	}
+	if filter.ID != "4638878" || filter.Name != "Today" || filter.Query != "today" {
+		t.Errorf("Unexpected filter: %+v", filter)
+	}
+
+	if err := api.UpdateFilter(ctx, "1", FilterRequest{Query: stringPtr("#Work | #Home")}); err != nil {
+		t.Fatalf("UpdateFilter: %v", err)
+	}
+	if err := api.DeleteFilter(ctx, "2"); err != nil {
+		t.Fatalf("DeleteFilter: %v", err)
+	}
+	if err := api.ReorderFilters(ctx, []string{"2", "1"}); err != nil {
+		t.Fatalf("ReorderFilters: %v", err)
+	}
+
+	tests := []struct {
+		Type string
+		Args string
+	}{
+		{"filter_add", `{"name":"Today","query":"today"}`},
+		{"filter_update", `{"id":"1","query":"#Work | #Home"}`},
+		{"filter_delete", `{"id":"2"}`},
+		{"filter_update_orders", `{"id_order_mapping":{"1":2,"2":1}}`},
+	}
+	if len(commands) != len(tests) {
+		t.Fatalf("Got %d commands, want %d", len(commands), len(tests))
+	}
+	for n, tc := range tests {
+		args, _ := json.Marshal(commands[n].Args)
+		if commands[n].Type != tc.Type || string(args) != tc.Args {
+			t.Errorf("Command %d = %s %s, want %s %s",
+				n, commands[n].Type, args, tc.Type, tc.Args)
+		}
+	}
+}
+
+func TestFilterValidation(t *testing.T) {
+	api := &TodoistClient{}
+	ctx := context.Background()
+
+	if _, err := api.CreateFilter(ctx, FilterRequest{Name: stringPtr("No query")}); err == nil {
+		t.Errorf("CreateFilter without query: expected error")
+	}
+	if err := api.UpdateFilter(ctx, "", FilterRequest{}); err == nil {
+		t.Errorf("UpdateFilter with empty ID: expected error")
+	}
+	if err := api.DeleteFilter(ctx, ""); err == nil {
+		t.Errorf("DeleteFilter with empty ID: expected error")
+	}
+	if err := api.ReorderFilters(ctx, nil); err == nil {
+		t.Errorf("ReorderFilters without IDs: expected error")
+	}
+}
+
+func TestRunFilter(t *testing.T) {
+	var commands []Command
+	var queries []string
+	api := newServerClient(t, filterServer(t, &commands, &queries))
+
+	tasks, err := api.RunFilter(context.Background(), "urgent")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := []string{"p1 & today"}; !reflect.DeepEqual(queries, want) {
+		t.Errorf("queries = %q, want %q", queries, want)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "100" {
+		t.Errorf("Unexpected tasks: %+v", tasks)
+	}
+
+	if _, err := api.RunFilter(context.Background(), "Missing"); err == nil {
+		t.Errorf("Expected error for missing filter")
+	}
+}
+
+func TestRunFilterMultipleQueries(t *testing.T) {
+	var commands []Command
+	var queries []string
+	api := newServerClient(t, filterServer(t, &commands, &queries))
+
+	tasks, err := api.RunFilter(context.Background(), "Due")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{"today", "overdue", `(p1 | p2) & #Work\, Home`}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("queries = %q, want %q", queries, want)
+	}
+	if got := taskIDs(tasks); !reflect.DeepEqual(got, []string{"100", "101", "102"}) {
+		t.Errorf("Task IDs = %v", got)
+	}
+}
+
+func TestSplitFilterQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"today", []string{"today"}},
+		{"today, overdue", []string{"today", "overdue"}},
+		{"(today, overdue) & #Work, p1", []string{"(today, overdue) & #Work", "p1"}},
+		{`@a\,b, p1,`, []string{`@a\,b`, "p1"}},
+		{" , ", []string{}},
+	}
+
+	for _, tc := range tests {
+		if got := splitFilterQuery(tc.query); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("splitFilterQuery(%q) = %q, want %q", tc.query, got, tc.want)
+		}
+	}
+}
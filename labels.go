@@ -18,6 +18,10 @@ package tdapi
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
 )
 
 // A PersonalLabel represents a Todoist personal label.
@@ -71,3 +75,188 @@ func (c *TodoistClient) GetPersonalLabel(id string) (response PersonalLabel, err
 
 	return response, err
 }
+
+// A CreatePersonalLabelRequest contains the fields to create a personal label.
+// See https://developer.todoist.com/rest/v2/?shell#create-a-new-personal-label
+type CreatePersonalLabelRequest struct {
+	Name       string `json:"name"`
+	Order      int    `json:"order,omitempty"`
+	Color      string `json:"color,omitempty"`
+	IsFavorite bool   `json:"is_favorite,omitempty"`
+}
+
+// An UpdatePersonalLabelRequest contains the fields to update a personal
+// label. Nil fields are not changed. Renaming a label also renames it on
+// the tasks that use it.
+// See https://developer.todoist.com/rest/v2/?shell#update-a-personal-label
+type UpdatePersonalLabelRequest struct {
+	Name       *string `json:"name,omitempty"`
+	Order      *int    `json:"order,omitempty"`
+	Color      *string `json:"color,omitempty"`
+	IsFavorite *bool   `json:"is_favorite,omitempty"`
+}
+
+// DeletePersonalLabelOptions are the options for DeletePersonalLabel.
+type DeletePersonalLabelOptions struct {
+	// CountTasks counts the active tasks using the label before it is
+	// deleted.
+	CountTasks bool
+}
+
+// CreatePersonalLabel creates a new personal label, returning the created label.
+func (c *TodoistClient) CreatePersonalLabel(r CreatePersonalLabelRequest) (response PersonalLabel, err error) {
+	var body []byte
+
+	if r.Name == "" {
+		return response, fmt.Errorf("empty label name")
+	}
+
+	body, err = c.postJSON("/labels", r)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, &response)
+
+	return response, err
+}
+
+// UpdatePersonalLabel updates a label by ID, returning the updated label.
+func (c *TodoistClient) UpdatePersonalLabel(id string, r UpdatePersonalLabelRequest) (response PersonalLabel, err error) {
+	var body []byte
+
+	if id == "" {
+		return response, fmt.Errorf("empty label ID")
+	}
+
+	body, err = c.postJSON("/labels/"+id, r)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, &response)
+
+	return response, err
+}
+
+// DeletePersonalLabel deletes a label by ID, which also removes it from
+// all tasks. If opts.CountTasks is set, the number of active tasks using
+// the label before it was deleted is returned, otherwise zero is returned.
+func (c *TodoistClient) DeletePersonalLabel(id string, opts *DeletePersonalLabelOptions) (taskCount int, err error) {
+	if id == "" {
+		return 0, fmt.Errorf("empty label ID")
+	}
+
+	if opts != nil && opts.CountTasks {
+		label, err := c.GetPersonalLabel(id)
+		if err != nil {
+			return 0, err
+		}
+
+		tasks, err := c.GetActiveTasks(&TaskParameters{Label: label.Name})
+		if err != nil {
+			return 0, err
+		}
+		taskCount = len(tasks)
+	}
+
+	_, err = c.Delete("/labels/"+id, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return taskCount, nil
+}
+
+// RenameSharedLabel renames all instances of the shared label oldName to
+// newName.
+func (c *TodoistClient) RenameSharedLabel(oldName string, newName string) error {
+	if oldName == "" || newName == "" {
+		return fmt.Errorf("empty label name")
+	}
+
+	_, err := c.postJSON("/labels/shared/rename",
+		map[string]string{"name": oldName, "new_name": newName})
+
+	return err
+}
+
+// RemoveSharedLabel removes all instances of the shared label name from
+// the tasks that use it.
+func (c *TodoistClient) RemoveSharedLabel(name string) error {
+	if name == "" {
+		return fmt.Errorf("empty label name")
+	}
+
+	_, err := c.postJSON("/labels/shared/remove", map[string]string{"name": name})
+
+	return err
+}
+
+// A LabelAudit reports shared labels that may need to be cleaned up.
+type LabelAudit struct {
+	// Unmatched are shared labels without a personal label of the same name.
+	Unmatched []string
+
+	// Similar are groups of shared and personal label names that differ
+	// only by case or whitespace.
+	Similar [][]string
+}
+
+// normalizeLabel returns name in lower case without whitespace.
+func normalizeLabel(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+// AuditSharedLabels compares shared labels with personal labels, returning
+// shared labels without a matching personal label and label names that
+// differ only by case or whitespace.
+func AuditSharedLabels(shared []string, personal []PersonalLabel) LabelAudit {
+	var audit LabelAudit
+
+	personalNames := make(map[string]bool, len(personal))
+	for _, label := range personal {
+		personalNames[label.Name] = true
+	}
+
+	namesByKey := make(map[string][]string)
+	seen := make(map[string]bool)
+	addName := func(name string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+
+		key := normalizeLabel(name)
+		namesByKey[key] = append(namesByKey[key], name)
+	}
+
+	for _, name := range shared {
+		if !personalNames[name] && !seen[name] {
+			audit.Unmatched = append(audit.Unmatched, name)
+		}
+		addName(name)
+	}
+	for _, label := range personal {
+		addName(label.Name)
+	}
+
+	for _, names := range namesByKey {
+		if len(names) > 1 {
+			sort.Strings(names)
+			audit.Similar = append(audit.Similar, names)
+		}
+	}
+	sort.Slice(audit.Similar, func(i, j int) bool {
+		return audit.Similar[i][0] < audit.Similar[j][0]
+	})
+
+	sort.Strings(audit.Unmatched)
+
+	return audit
+}
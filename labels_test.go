@@ -2,6 +2,7 @@ package tdapi
 
 import (
 	_ "embed"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/url"
@@ -140,3 +141,169 @@ func TestGetPersonalLabel(t *testing.T) {
 		t.Errorf("Labels do not match.\n Got: %+v\nWant: %+v\n", labels, expectedLabels)
 	}
 }
+
+func TestCreatePersonalLabel(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/v2/labels" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var got CreatePersonalLabelRequest
+		json.NewDecoder(r.Body).Decode(&got)
+
+		want := CreatePersonalLabelRequest{Name: "Label 3", Color: "red", Order: 3, IsFavorite: true}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Request does not match.\n Got: %+v\nWant: %+v\n", got, want)
+		}
+
+		io.WriteString(w, `{"id":"3","name":"Label 3","color":"red","order":3,"is_favorite":true}`)
+	}))
+
+	label, err := api.CreatePersonalLabel(CreatePersonalLabelRequest{Name: "Label 3", Color: "red", Order: 3, IsFavorite: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedLabel := PersonalLabel{ID: "3", Name: "Label 3", Color: "red", Order: 3, IsFavorite: true}
+	if !reflect.DeepEqual(label, expectedLabel) {
+		t.Errorf("Labels do not match.\n Got: %+v\nWant: %+v\n", label, expectedLabel)
+	}
+}
+
+func TestUpdatePersonalLabel(t *testing.T) {
+	order := 0
+
+	tests := []struct {
+		name     string
+		request  UpdatePersonalLabelRequest
+		wantBody map[string]interface{}
+	}{
+		{"rename", UpdatePersonalLabelRequest{Name: stringPtr("Label 4")}, map[string]interface{}{"name": "Label 4"}},
+		{"zero values", UpdatePersonalLabelRequest{Order: &order, IsFavorite: boolPtr(false)}, map[string]interface{}{"order": 0.0, "is_favorite": false}},
+		{"color", UpdatePersonalLabelRequest{Color: stringPtr("blue")}, map[string]interface{}{"color": "blue"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			api := newServerClient(t, requestHandler(t, http.MethodPost, "/rest/v2/labels/3", tc.wantBody,
+				http.StatusOK, `{"id":"3","name":"Label 4","color":"blue","order":0,"is_favorite":false}`))
+
+			label, err := api.UpdatePersonalLabel("3", tc.request)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			expectedLabel := PersonalLabel{ID: "3", Name: "Label 4", Color: "blue"}
+			if !reflect.DeepEqual(label, expectedLabel) {
+				t.Errorf("Labels do not match.\n Got: %+v\nWant: %+v\n", label, expectedLabel)
+			}
+		})
+	}
+
+	if _, err := (&TodoistClient{}).UpdatePersonalLabel("", UpdatePersonalLabelRequest{}); err == nil {
+		t.Errorf("UpdatePersonalLabel(\"\") did not return an error")
+	}
+}
+
+func TestDeletePersonalLabel(t *testing.T) {
+	deleted := false
+
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/v2/labels/1234567890":
+			http.ServeFile(w, r, "testdata/label1.json")
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/v2/tasks":
+			if got := r.URL.Query().Get("label"); got != "Personal_Label_1" {
+				t.Errorf("label = %q, want %q", got, "Personal_Label_1")
+			}
+			io.WriteString(w, `[{"id":"1"},{"id":"2"}]`)
+		case r.Method == http.MethodDelete && r.URL.Path == "/rest/v2/labels/1234567890":
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+
+	count, err := api.DeletePersonalLabel("1234567890", &DeletePersonalLabelOptions{CountTasks: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("count = %d, want 2", count)
+	}
+	if !deleted {
+		t.Errorf("Label was not deleted")
+	}
+}
+
+func TestRenameSharedLabel(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/v2/labels/shared/rename" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var got map[string]string
+		json.NewDecoder(r.Body).Decode(&got)
+
+		want := map[string]string{"name": "Folow Up", "new_name": "Follow Up"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Request does not match.\n Got: %+v\nWant: %+v\n", got, want)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	if err := api.RenameSharedLabel("Folow Up", "Follow Up"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestRemoveSharedLabel(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/v2/labels/shared/remove" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var got map[string]string
+		json.NewDecoder(r.Body).Decode(&got)
+
+		want := map[string]string{"name": "Follow Up"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Request does not match.\n Got: %+v\nWant: %+v\n", got, want)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	if err := api.RemoveSharedLabel("Follow Up"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := api.RemoveSharedLabel(""); err == nil {
+		t.Errorf("RemoveSharedLabel(\"\") did not return an error")
+	}
+}
+
+func TestAuditSharedLabels(t *testing.T) {
+	shared := []string{"Follow Up", "follow up", "Urgent", "Waiting", "Waiting"}
+	personal := []PersonalLabel{
+		{Name: "Urgent"},
+		{Name: "FollowUp"},
+		{Name: "waiting "},
+	}
+
+	audit := AuditSharedLabels(shared, personal)
+
+	expectedAudit := LabelAudit{
+		Unmatched: []string{"Follow Up", "Waiting", "follow up"},
+		Similar: [][]string{
+			{"Follow Up", "FollowUp", "follow up"},
+			{"Waiting", "waiting "},
+		},
+	}
+
+	if !reflect.DeepEqual(audit, expectedAudit) {
+		t.Errorf("Audit does not match.\n Got: %+v\nWant: %+v\n", audit, expectedAudit)
+	}
+}
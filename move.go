@@ -0,0 +1,210 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// A MoveTarget is the destination of a moved task.
+// Exactly one of ProjectID, SectionID, or ParentID must be set.
+type MoveTarget struct {
+	ProjectID string
+	SectionID string
+	ParentID  string
+}
+
+// args returns the item_move arguments to move the task with id to the target.
+func (t MoveTarget) args(id string) (map[string]string, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty task ID")
+	}
+
+	args := map[string]string{"id": id}
+
+	n := 0
+	if t.ProjectID != "" {
+		args["project_id"] = t.ProjectID
+		n++
+	}
+	if t.SectionID != "" {
+		args["section_id"] = t.SectionID
+		n++
+	}
+	if t.ParentID != "" {
+		args["parent_id"] = t.ParentID
+		n++
+	}
+
+	if n != 1 {
+		return nil, fmt.Errorf("move target must set exactly one of project, section, or parent ID")
+	}
+
+	if t.ParentID == id {
+		return nil, fmt.Errorf("cannot move task %s under itself", id)
+	}
+
+	return args, nil
+}
+
+// MoveTask moves the task with id, and its subtasks, to target.
+// MoveTask has no dry run; use MoveTasks with MoveOptions.DryRun, or
+// PlanMoves, to report what would move first.
+// See https://developer.todoist.com/sync/v9/#move-an-item
+func (c *TodoistClient) MoveTask(ctx context.Context, id string, target MoveTarget) error {
+	args, err := target.args(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.executeCommand(ctx, NewCommand("item_move", args))
+
+	return err
+}
+
+// A TaskMove is a task to move and its destination.
+type TaskMove struct {
+	TaskID string
+	Target MoveTarget
+}
+
+// MoveOptions control how MoveTasks moves tasks.
+type MoveOptions struct {
+	// DryRun reports what would move without moving any tasks.
+	DryRun bool
+}
+
+// A MoveResult reports the tasks moved, or that would move, for a TaskMove.
+type MoveResult struct {
+	TaskMove
+
+	// TaskIDs are the IDs of the task and its subtasks, in depth-first order.
+	TaskIDs []string
+
+	// Err is the reason the task was not moved, or nil if it was moved.
+	Err error
+}
+
+// PlanMoves returns what moves would move given the active tasks, without
+// moving any tasks. A move fails if the task is unknown, the target is
+// invalid, or the target parent is the task or one of its subtasks.
+func PlanMoves(tasks []Task, moves []TaskMove) []MoveResult {
+	tree := NewTaskTree(tasks)
+
+	results := make([]MoveResult, 0, len(moves))
+	for _, move := range moves {
+		result := MoveResult{TaskMove: move}
+
+		_, err := move.Target.args(move.TaskID)
+		if err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+
+		if _, ok := tree.Task(move.TaskID); !ok {
+			result.Err = fmt.Errorf("unknown task %s", move.TaskID)
+			results = append(results, result)
+			continue
+		}
+
+		result.TaskIDs = []string{move.TaskID}
+		for _, task := range tree.Descendants(move.TaskID) {
+			result.TaskIDs = append(result.TaskIDs, task.ID)
+			if task.ID == move.Target.ParentID {
+				result.Err = fmt.Errorf(
+					"cannot move task %s under its subtask %s",
+					move.TaskID, task.ID)
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// syncActiveTasks returns the active tasks using the Sync API.
+func (c *TodoistClient) syncActiveTasks(ctx context.Context) ([]Task, error) {
+	response, err := c.Sync(ctx, "", []string{ResourceItems})
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]Task, 0, len(response.Items))
+	for _, item := range response.Items {
+		if !item.Checked && !item.IsDeleted {
+			tasks = append(tasks, item.Task())
+		}
+	}
+
+	return tasks, nil
+}
+
+// MoveTasks moves several tasks, and their subtasks, using as few requests
+// as possible.
+//
+// The active tasks are retrieved to validate the moves and report the
+// subtasks that move. Moves that fail validation are not sent. With
+// opts.DryRun, the results report what would move and no tasks are moved.
+//
+// The returned error is for a failed request. If a later request fails,
+// the results are still returned, with ErrCommandNotSent for the moves
+// that were not sent. Check MoveResult.Err for the result of each move.
+func (c *TodoistClient) MoveTasks(ctx context.Context, moves []TaskMove, opts *MoveOptions) ([]MoveResult, error) {
+	tasks, err := c.syncActiveTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := PlanMoves(tasks, moves)
+	if opts != nil && opts.DryRun {
+		return results, nil
+	}
+
+	batch := NewCommandBatch()
+	uuids := make([]string, len(results))
+	for n, result := range results {
+		if result.Err != nil {
+			continue
+		}
+
+		args, _ := result.Target.args(result.TaskID)
+		uuids[n] = batch.Add("item_move", args)
+	}
+
+	if batch.Len() == 0 {
+		return results, nil
+	}
+
+	// the batch result includes the moves sent before any failed request
+	batchResult, err := c.ExecuteBatch(ctx, batch)
+
+	errByUUID := make(map[string]error, len(batchResult.Statuses))
+	for _, status := range batchResult.Statuses {
+		errByUUID[status.Command.UUID] = status.Err
+	}
+
+	for n := range results {
+		if uuids[n] != "" {
+			results[n].Err = errByUUID[uuids[n]]
+		}
+	}
+
+	return results, err
+}
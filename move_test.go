@@ -0,0 +1,193 @@
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPlanMoves(t *testing.T) {
+	tests := []struct {
+		name    string
+		move    TaskMove
+		want    []string
+		wantErr bool
+	}{
+		{"with subtasks", TaskMove{"a", MoveTarget{ProjectID: "p2"}}, []string{"a", "a1", "a1x", "a2"}, false},
+		{"to parent", TaskMove{"b", MoveTarget{ParentID: "a"}}, []string{"b"}, false},
+		{"under subtask", TaskMove{"a", MoveTarget{ParentID: "a1x"}}, []string{"a", "a1", "a1x", "a2"}, true},
+		{"under itself", TaskMove{"a", MoveTarget{ParentID: "a"}}, nil, true},
+		{"no target", TaskMove{"a", MoveTarget{}}, nil, true},
+		{"two targets", TaskMove{"a", MoveTarget{ProjectID: "p", SectionID: "s"}}, nil, true},
+		{"unknown task", TaskMove{"zzz", MoveTarget{ProjectID: "p"}}, nil, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results := PlanMoves(testTasks(), []TaskMove{tc.move})
+			if len(results) != 1 {
+				t.Fatalf("Got %d results, want 1", len(results))
+			}
+
+			if !reflect.DeepEqual(results[0].TaskIDs, tc.want) {
+				t.Errorf("TaskIDs.\n Got: %v\nWant: %v", results[0].TaskIDs, tc.want)
+			}
+			if (results[0].Err != nil) != tc.wantErr {
+				t.Errorf("Err = %v, wantErr %v", results[0].Err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// syncItems returns tasks as Sync API items.
+func syncItems(tasks []Task) []SyncItem {
+	items := make([]SyncItem, 0, len(tasks))
+	for _, task := range tasks {
+		items = append(items, SyncItem{
+			ID:         task.ID,
+			ProjectID:  task.ProjectID,
+			SectionID:  task.SectionID,
+			ParentID:   task.ParentID,
+			Checked:    task.IsCompleted,
+			ChildOrder: task.Order,
+		})
+	}
+	return items
+}
+
+func TestMoveTasks(t *testing.T) {
+	var commands []Command
+
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sync/v9/sync" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+			return
+		}
+
+		if r.FormValue("commands") == "" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"sync_token": "token",
+				"full_sync":  true,
+				"items":      syncItems(testTasks()),
+			})
+			return
+		}
+
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil || len(commands) != 2 {
+			t.Errorf("Cannot decode commands: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		status := map[string]interface{}{
+			commands[0].UUID: "ok",
+			commands[1].UUID: map[string]interface{}{"error_code": 20, "error": "Project not found"},
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"sync_status": status})
+	}))
+
+	moves := []TaskMove{
+		{"a", MoveTarget{SectionID: "s1"}},
+		{"b", MoveTarget{ProjectID: "missing"}},
+		{"a", MoveTarget{ParentID: "a1"}},
+	}
+
+	results, err := api.MoveTasks(context.Background(), moves, &MoveOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if commands != nil {
+		t.Errorf("Dry run sent commands: %v", commands)
+	}
+	if results[0].Err != nil || len(results[0].TaskIDs) != 4 {
+		t.Errorf("Dry run result = %+v", results[0])
+	}
+
+	results, err = api.MoveTasks(context.Background(), moves, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(commands) != 2 {
+		t.Fatalf("Got %d commands, want 2", len(commands))
+	}
+	wantArgs := map[string]interface{}{"id": "a", "section_id": "s1"}
+	if commands[0].Type != "item_move" || !reflect.DeepEqual(commands[0].Args, wantArgs) {
+		t.Errorf("Command = %+v", commands[0])
+	}
+
+	if results[0].Err != nil {
+		t.Errorf("Unexpected error: %v", results[0].Err)
+	}
+	if _, ok := results[1].Err.(*CommandError); !ok {
+		t.Errorf("Err = %v, want *CommandError", results[1].Err)
+	}
+	if results[2].Err == nil {
+		t.Errorf("Err = nil, want error for move under subtask")
+	}
+}
+
+func TestMoveTasksPartialFailure(t *testing.T) {
+	var tasks []Task
+	var moves []TaskMove
+	for n := 0; n < MaxCommandsPerRequest+5; n++ {
+		id := fmt.Sprintf("t%d", n)
+		tasks = append(tasks, Task{ID: id, Order: n})
+		moves = append(moves, TaskMove{id, MoveTarget{ProjectID: "p2"}})
+	}
+
+	requests := 0
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("commands") == "" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"sync_token": "token",
+				"full_sync":  true,
+				"items":      syncItems(tasks),
+			})
+			return
+		}
+
+		requests++
+		if requests > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var commands []Command
+		json.Unmarshal([]byte(r.FormValue("commands")), &commands)
+		status := make(map[string]string)
+		for _, cmd := range commands {
+			status[cmd.UUID] = "ok"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"sync_status": status})
+	}))
+
+	results, err := api.MoveTasks(context.Background(), moves, nil)
+	if err == nil {
+		t.Fatalf("Expected error for failed request")
+	}
+	if len(results) != len(moves) {
+		t.Fatalf("Got %d results, want %d", len(results), len(moves))
+	}
+	if results[0].Err != nil {
+		t.Errorf("First move Err = %v, want nil", results[0].Err)
+	}
+	if last := results[len(results)-1]; last.Err != ErrCommandNotSent {
+		t.Errorf("Last move Err = %v, want ErrCommandNotSent", last.Err)
+	}
+}
+
+func TestMoveTaskInvalidTarget(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s", r.URL.Path)
+		io.WriteString(w, "{}")
+	}))
+
+	err := api.MoveTask(context.Background(), "a", MoveTarget{})
+	if err == nil {
+		t.Errorf("Expected error for empty target")
+	}
+}
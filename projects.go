@@ -18,6 +18,7 @@ package tdapi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 )
 
@@ -36,6 +37,7 @@ type Project struct {
 	IsTeamInbox    bool    `json:"is_team_inbox"`
 	ViewStyle      string  `json:"view_style"`
 	URL            string  `json:"url"`
+	IsArchived     bool    `json:"is_archived"`
 }
 
 // GetProjects returns all user projects.
@@ -71,6 +73,95 @@ func (c *TodoistClient) GetProject(project_id string) (Project, error) {
 	return project, err
 }
 
+// View styles for a project.
+const (
+	ViewStyleList  = "list"
+	ViewStyleBoard = "board"
+)
+
+// ErrNotConfirmed is returned when a destructive operation is requested
+// without confirmation.
+var ErrNotConfirmed = errors.New("operation not confirmed")
+
+// A CreateProjectRequest contains the fields to create a project.
+// See https://developer.todoist.com/rest/v2/?shell#create-a-new-project
+type CreateProjectRequest struct {
+	Name       string `json:"name"`
+	ParentID   string `json:"parent_id,omitempty"`
+	Color      string `json:"color,omitempty"`
+	IsFavorite bool   `json:"is_favorite,omitempty"`
+	ViewStyle  string `json:"view_style,omitempty"`
+}
+
+// An UpdateProjectRequest contains the fields to update a project.
+// Nil fields are not changed. The parent of a project cannot be updated.
+// See https://developer.todoist.com/rest/v2/?shell#update-a-project
+type UpdateProjectRequest struct {
+	Name       *string `json:"name,omitempty"`
+	Color      *string `json:"color,omitempty"`
+	IsFavorite *bool   `json:"is_favorite,omitempty"`
+	ViewStyle  *string `json:"view_style,omitempty"`
+}
+
+// DeleteProjectOptions are the options for DeleteProject.
+type DeleteProjectOptions struct {
+	// Confirm must be true to delete the project, because deleting a
+	// project also deletes all of its sections and tasks.
+	Confirm bool
+}
+
+// CreateProject creates a new project, returning the created project.
+func (c *TodoistClient) CreateProject(r CreateProjectRequest) (Project, error) {
+	if r.Name == "" {
+		return Project{}, fmt.Errorf("empty project name")
+	}
+
+	body, err := c.postJSON("/projects", r)
+	if err != nil {
+		return Project{}, err
+	}
+
+	var project Project
+	err = json.Unmarshal(body, &project)
+
+	return project, err
+}
+
+// UpdateProject updates the project for the given project_id, returning
+// the updated project.
+func (c *TodoistClient) UpdateProject(project_id string, r UpdateProjectRequest) (Project, error) {
+	if project_id == "" {
+		return Project{}, fmt.Errorf("empty project ID")
+	}
+
+	body, err := c.postJSON("/projects/"+project_id, r)
+	if err != nil {
+		return Project{}, err
+	}
+
+	var project Project
+	err = json.Unmarshal(body, &project)
+
+	return project, err
+}
+
+// DeleteProject deletes the project for the given project_id, along with
+// all of its sections and tasks. ErrNotConfirmed is returned unless
+// opts.Confirm is true.
+func (c *TodoistClient) DeleteProject(project_id string, opts DeleteProjectOptions) error {
+	if project_id == "" {
+		return fmt.Errorf("empty project ID")
+	}
+
+	if !opts.Confirm {
+		return ErrNotConfirmed
+	}
+
+	_, err := c.Delete("/projects/"+project_id, nil)
+
+	return err
+}
+
 // ProjectByID returns a map to allow lookup of projects by ID.
 func ProjectByID(projects []Project) map[string]Project {
 	projectByID := make(map[string]Project, len(projects))
@@ -83,17 +174,15 @@ func ProjectByID(projects []Project) map[string]Project {
 
 // ChildProjectIDs returns a map of child IDs for each parent ID.
 // An empty parent ID contains all the top level projects.
+//
+// Deprecated: Use NewProjectTree, which also provides ordering, paths,
+// and detection of inconsistent parent IDs.
 func ChildProjectIDs(projects []Project) map[string][]string {
-	projectByID := ProjectByID(projects)
+	tree := NewProjectTree(projects)
 
-	childProjectIDs := make(map[string][]string)
-	for _, project := range projects {
-		parentID := ""
-		if project.ParentID != nil {
-			parentID = *projectByID[project.ID].ParentID
-		}
-		childProjectIDs[parentID] =
-			append(childProjectIDs[parentID], project.ID)
+	childProjectIDs := make(map[string][]string, len(tree.childIDs))
+	for parentID, childIDs := range tree.childIDs {
+		childProjectIDs[parentID] = append([]string(nil), childIDs...)
 	}
 
 	return childProjectIDs
@@ -0,0 +1,192 @@
+package tdapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func stringPtr(s string) *string { return &s }
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestCreateProject(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  CreateProjectRequest
+		wantBody map[string]interface{}
+		status   int
+		body     string
+		want     Project
+		wantErr  bool
+	}{
+		{
+			name:     "name only",
+			request:  CreateProjectRequest{Name: "Acme"},
+			wantBody: map[string]interface{}{"name": "Acme"},
+			status:   http.StatusOK,
+			body:     `{"id":"1","name":"Acme","view_style":"list"}`,
+			want:     Project{ID: "1", Name: "Acme", ViewStyle: ViewStyleList},
+		},
+		{
+			name: "all fields",
+			request: CreateProjectRequest{
+				Name: "Acme", ParentID: "9", Color: "red",
+				IsFavorite: true, ViewStyle: ViewStyleBoard,
+			},
+			wantBody: map[string]interface{}{
+				"name": "Acme", "parent_id": "9", "color": "red",
+				"is_favorite": true, "view_style": "board",
+			},
+			status: http.StatusOK,
+			body:   `{"id":"1","name":"Acme","parent_id":"9","color":"red","is_favorite":true,"view_style":"board"}`,
+			want: Project{
+				ID: "1", Name: "Acme", ParentID: stringPtr("9"), Color: "red",
+				IsFavorite: true, ViewStyle: ViewStyleBoard,
+			},
+		},
+		{
+			name:     "server error",
+			request:  CreateProjectRequest{Name: "Acme"},
+			wantBody: map[string]interface{}{"name": "Acme"},
+			status:   http.StatusBadRequest,
+			body:     "Invalid argument value",
+			wantErr:  true,
+		},
+		{
+			name:    "empty name",
+			request: CreateProjectRequest{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			handler := requestHandler(t, http.MethodPost, "/rest/v2/projects", tc.wantBody, tc.status, tc.body)
+			api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				handler(w, r)
+			}))
+
+			got, err := api.CreateProject(tc.request)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("CreateProject() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantBody == nil && called {
+				t.Errorf("Unexpected request for invalid project")
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Project does not match.\n Got: %+v\nWant: %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateProject(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		request  UpdateProjectRequest
+		wantBody map[string]interface{}
+		status   int
+		body     string
+		want     Project
+		wantErr  bool
+	}{
+		{
+			name:     "rename",
+			id:       "1",
+			request:  UpdateProjectRequest{Name: stringPtr("Acme Corp")},
+			wantBody: map[string]interface{}{"name": "Acme Corp"},
+			status:   http.StatusOK,
+			body:     `{"id":"1","name":"Acme Corp"}`,
+			want:     Project{ID: "1", Name: "Acme Corp"},
+		},
+		{
+			name:     "unfavorite",
+			id:       "1",
+			request:  UpdateProjectRequest{IsFavorite: boolPtr(false), ViewStyle: stringPtr(ViewStyleBoard)},
+			wantBody: map[string]interface{}{"is_favorite": false, "view_style": "board"},
+			status:   http.StatusOK,
+			body:     `{"id":"1","name":"Acme","view_style":"board"}`,
+			want:     Project{ID: "1", Name: "Acme", ViewStyle: ViewStyleBoard},
+		},
+		{
+			name:     "not found",
+			id:       "1",
+			request:  UpdateProjectRequest{Color: stringPtr("red")},
+			wantBody: map[string]interface{}{"color": "red"},
+			status:   http.StatusNotFound,
+			body:     "Project not found",
+			wantErr:  true,
+		},
+		{
+			name:    "empty ID",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			api := newServerClient(t, requestHandler(t, http.MethodPost, "/rest/v2/projects/"+tc.id, tc.wantBody, tc.status, tc.body))
+
+			got, err := api.UpdateProject(tc.id, tc.request)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("UpdateProject() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Project does not match.\n Got: %+v\nWant: %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeleteProject(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		opts       DeleteProjectOptions
+		status     int
+		wantCalled bool
+		wantErr    error
+	}{
+		{"confirmed", "1", DeleteProjectOptions{Confirm: true}, http.StatusNoContent, true, nil},
+		{"not confirmed", "1", DeleteProjectOptions{}, http.StatusNoContent, false, ErrNotConfirmed},
+		{"forbidden", "1", DeleteProjectOptions{Confirm: true}, http.StatusForbidden, true, &APIErrorResponse{StatusCode: http.StatusForbidden}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				if r.Method != http.MethodDelete || r.URL.Path != "/rest/v2/projects/"+tc.id {
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tc.status)
+			}))
+
+			err := api.DeleteProject(tc.id, tc.opts)
+			if called != tc.wantCalled {
+				t.Errorf("called = %v, want %v", called, tc.wantCalled)
+			}
+
+			switch want := tc.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			case *APIErrorResponse:
+				apiErr, ok := err.(*APIErrorResponse)
+				if !ok || apiErr.StatusCode != want.StatusCode {
+					t.Errorf("Err = %v, want status %d", err, want.StatusCode)
+				}
+			default:
+				if err != want {
+					t.Errorf("Err = %v, want %v", err, want)
+				}
+			}
+		})
+	}
+}
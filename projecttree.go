@@ -0,0 +1,269 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProjectPathSeparator separates project names in a project path.
+const ProjectPathSeparator = "/"
+
+// ProjectWalkFunc is the type of the function called by ProjectTree.Walk
+// for each project. depth is zero for top level projects.
+//
+// If the function returns SkipChildren, the children of the project are
+// not visited. Any other non-nil error stops the walk and is returned by
+// Walk.
+type ProjectWalkFunc func(project Project, depth int) error
+
+// A ProjectTreeError reports inconsistent parent IDs in a ProjectTree.
+type ProjectTreeError struct {
+	// OrphanIDs are the projects whose parent is missing.
+	OrphanIDs []string
+
+	// Cycles are the projects in each cycle of parent IDs.
+	Cycles [][]string
+}
+
+// Error returns a string representation of the error.
+func (e *ProjectTreeError) Error() string {
+	var msgs []string
+
+	if len(e.OrphanIDs) > 0 {
+		msgs = append(msgs, fmt.Sprintf("projects with missing parent: %s",
+			strings.Join(e.OrphanIDs, ", ")))
+	}
+
+	for _, cycle := range e.Cycles {
+		msgs = append(msgs, fmt.Sprintf("parent cycle: %s",
+			strings.Join(cycle, " -> ")))
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// A ProjectTree organizes a flat list of projects into a hierarchy using
+// ParentID. Archived projects are included, so use Project.IsArchived to
+// include or exclude them.
+//
+// Building a ProjectTree never fails. Projects whose parent is missing
+// are treated as top level projects, and a parent cycle is broken by
+// treating the project with the lowest ID in the cycle as a top level
+// project. Use Validate to report these problems.
+type ProjectTree struct {
+	projectByID map[string]Project
+	parentIDs   map[string]string
+	childIDs    map[string][]string
+	idByPath    map[string]string
+	treeErr     ProjectTreeError
+}
+
+// NewProjectTree returns a ProjectTree built from projects.
+// Children are sorted by Order, then by ID for projects with the same Order.
+func NewProjectTree(projects []Project) *ProjectTree {
+	tree := &ProjectTree{
+		projectByID: ProjectByID(projects),
+		parentIDs:   make(map[string]string),
+		childIDs:    make(map[string][]string),
+		idByPath:    make(map[string]string),
+	}
+
+	for _, project := range projects {
+		if project.ParentID == nil || *project.ParentID == "" {
+			continue
+		}
+
+		parentID := *project.ParentID
+		if _, ok := tree.projectByID[parentID]; !ok {
+			tree.treeErr.OrphanIDs = append(tree.treeErr.OrphanIDs, project.ID)
+			continue
+		}
+
+		tree.parentIDs[project.ID] = parentID
+	}
+
+	ids := make([]string, 0, len(projects))
+	for _, project := range projects {
+		ids = append(ids, project.ID)
+	}
+	tree.treeErr.Cycles = breakParentCycles(ids, tree.parentIDs)
+
+	seen := make(map[string]bool, len(projects))
+	for _, project := range projects {
+		if seen[project.ID] {
+			continue
+		}
+		seen[project.ID] = true
+
+		parentID := tree.parentIDs[project.ID]
+		tree.childIDs[parentID] = append(tree.childIDs[parentID], project.ID)
+	}
+
+	for _, ids := range tree.childIDs {
+		sortByOrder(ids, tree.projectOrder)
+	}
+
+	// the first project in walk order wins for duplicate paths
+	tree.Walk(func(project Project, depth int) error {
+		path := tree.Path(project.ID)
+		if _, ok := tree.idByPath[path]; !ok {
+			tree.idByPath[path] = project.ID
+		}
+		return nil
+	})
+
+	return tree
+}
+
+// projectOrder returns the Order of the project with id.
+func (t *ProjectTree) projectOrder(id string) int {
+	return t.projectByID[id].Order
+}
+
+// projects returns the projects for ids.
+func (t *ProjectTree) projects(ids []string) []Project {
+	projects := make([]Project, 0, len(ids))
+	for _, id := range ids {
+		projects = append(projects, t.projectByID[id])
+	}
+	return projects
+}
+
+// Validate returns a *ProjectTreeError if any project has a missing
+// parent or is part of a parent cycle, or nil otherwise.
+func (t *ProjectTree) Validate() error {
+	if len(t.treeErr.OrphanIDs) == 0 && len(t.treeErr.Cycles) == 0 {
+		return nil
+	}
+
+	treeErr := t.treeErr
+	return &treeErr
+}
+
+// Project returns the project for id and whether it is in the tree.
+func (t *ProjectTree) Project(id string) (Project, bool) {
+	project, ok := t.projectByID[id]
+	return project, ok
+}
+
+// Roots returns the top level projects in order.
+func (t *ProjectTree) Roots() []Project {
+	return t.projects(t.childIDs[""])
+}
+
+// Children returns the direct children of the project with id, in order.
+func (t *ProjectTree) Children(id string) []Project {
+	if id == "" {
+		return nil
+	}
+	return t.projects(t.childIDs[id])
+}
+
+// Parent returns the parent of the project with id, and false if the
+// project is a top level project or is not in the tree.
+func (t *ProjectTree) Parent(id string) (Project, bool) {
+	parentID, ok := t.parentIDs[id]
+	if !ok {
+		return Project{}, false
+	}
+	return t.projectByID[parentID], true
+}
+
+// Ancestors returns the ancestors of the project with id, starting with
+// its parent and ending with its top level project.
+func (t *ProjectTree) Ancestors(id string) []Project {
+	var ancestors []Project
+
+	for {
+		parent, ok := t.Parent(id)
+		if !ok {
+			break
+		}
+		ancestors = append(ancestors, parent)
+		id = parent.ID
+	}
+
+	return ancestors
+}
+
+// Depth returns the depth of the project with id, where top level
+// projects have a depth of zero. Depth returns -1 if id is not in the tree.
+func (t *ProjectTree) Depth(id string) int {
+	if _, ok := t.projectByID[id]; !ok {
+		return -1
+	}
+	return len(t.Ancestors(id))
+}
+
+// Path returns the names of the project with id and its ancestors, from
+// the top level project down, joined by ProjectPathSeparator, e.g.,
+// "Work/Clients/Acme". Path returns an empty string if id is not in the
+// tree.
+func (t *ProjectTree) Path(id string) string {
+	project, ok := t.projectByID[id]
+	if !ok {
+		return ""
+	}
+
+	ancestors := t.Ancestors(id)
+
+	names := make([]string, len(ancestors)+1)
+	for n, ancestor := range ancestors {
+		names[len(ancestors)-1-n] = ancestor.Name
+	}
+	names[len(ancestors)] = project.Name
+
+	return strings.Join(names, ProjectPathSeparator)
+}
+
+// ProjectByPath returns the project for path, as returned by Path, and
+// whether it was found. If projects share a path, the first in walk order
+// is returned.
+func (t *ProjectTree) ProjectByPath(path string) (Project, bool) {
+	id, ok := t.idByPath[path]
+	if !ok {
+		return Project{}, false
+	}
+	return t.projectByID[id], true
+}
+
+// Walk visits every project in depth-first order, calling fn for each
+// project. See ProjectWalkFunc for how fn controls the walk.
+func (t *ProjectTree) Walk(fn ProjectWalkFunc) error {
+	var walk func(ids []string, depth int) error
+	walk = func(ids []string, depth int) error {
+		for _, id := range ids {
+			err := fn(t.projectByID[id], depth)
+			if err == SkipChildren {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			err = walk(t.childIDs[id], depth+1)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return walk(t.childIDs[""], 0)
+}
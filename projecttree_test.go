@@ -0,0 +1,143 @@
+package tdapi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// projectIDs returns the IDs of projects.
+func projectIDs(projects []Project) []string {
+	ids := make([]string, 0, len(projects))
+	for _, project := range projects {
+		ids = append(ids, project.ID)
+	}
+	return ids
+}
+
+func testProjects() []Project {
+	return []Project{
+		{ID: "acme", Name: "Acme", ParentID: stringPtr("clients"), Order: 2},
+		{ID: "clients", Name: "Clients", ParentID: stringPtr("work"), Order: 1},
+		{ID: "beta", Name: "Beta", ParentID: stringPtr("clients"), Order: 1},
+		{ID: "work", Name: "Work", Order: 2},
+		{ID: "inbox", Name: "Inbox", Order: 1},
+		{ID: "old", Name: "Old", ParentID: stringPtr("work"), Order: 0, IsArchived: true},
+	}
+}
+
+func TestProjectTree(t *testing.T) {
+	tree := NewProjectTree(testProjects())
+
+	if err := tree.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"Roots", projectIDs(tree.Roots()), []string{"inbox", "work"}},
+		{"Children", projectIDs(tree.Children("clients")), []string{"beta", "acme"}},
+		{"ChildrenWithArchived", projectIDs(tree.Children("work")), []string{"old", "clients"}},
+		{"Ancestors", projectIDs(tree.Ancestors("acme")), []string{"clients", "work"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.got, tc.want) {
+				t.Errorf("Got: %v\nWant: %v", tc.got, tc.want)
+			}
+		})
+	}
+
+	if parent, ok := tree.Parent("acme"); !ok || parent.ID != "clients" {
+		t.Errorf("Parent() = %v, %v, want clients", parent.ID, ok)
+	}
+	if _, ok := tree.Parent("work"); ok {
+		t.Errorf("Parent() of top level project returned ok")
+	}
+
+	if got := tree.Path("acme"); got != "Work/Clients/Acme" {
+		t.Errorf("Path() = %q, want %q", got, "Work/Clients/Acme")
+	}
+	if got := tree.Depth("acme"); got != 2 {
+		t.Errorf("Depth() = %d, want 2", got)
+	}
+
+	project, ok := tree.ProjectByPath("Work/Clients/Beta")
+	if !ok || project.ID != "beta" {
+		t.Errorf("ProjectByPath() = %v, %v, want beta", project.ID, ok)
+	}
+	if _, ok := tree.ProjectByPath("Work/Acme"); ok {
+		t.Errorf("ProjectByPath() found a project for an invalid path")
+	}
+}
+
+func TestProjectTreeWalk(t *testing.T) {
+	tree := NewProjectTree(testProjects())
+
+	var visited []string
+	err := tree.Walk(func(project Project, depth int) error {
+		if project.IsArchived {
+			return SkipChildren
+		}
+		visited = append(visited, project.ID)
+		if project.ID == "clients" {
+			return SkipChildren
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{"inbox", "work", "clients"}
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("Walk order.\n Got: %v\nWant: %v", visited, want)
+	}
+
+	stop := errors.New("stop")
+	err = tree.Walk(func(project Project, depth int) error {
+		return stop
+	})
+	if err != stop {
+		t.Errorf("Walk() error = %v, want %v", err, stop)
+	}
+}
+
+func TestProjectTreeInconsistent(t *testing.T) {
+	projects := []Project{
+		{ID: "a", Name: "A", ParentID: stringPtr("c")},
+		{ID: "b", Name: "B", ParentID: stringPtr("a")},
+		{ID: "c", Name: "C", ParentID: stringPtr("b")},
+		{ID: "d", Name: "D", ParentID: stringPtr("missing")},
+		{ID: "e", Name: "E", ParentID: stringPtr("e")},
+	}
+
+	tree := NewProjectTree(projects)
+
+	var treeErr *ProjectTreeError
+	if !errors.As(tree.Validate(), &treeErr) {
+		t.Fatalf("Validate() = %v, want *ProjectTreeError", tree.Validate())
+	}
+
+	if want := []string{"d"}; !reflect.DeepEqual(treeErr.OrphanIDs, want) {
+		t.Errorf("OrphanIDs = %v, want %v", treeErr.OrphanIDs, want)
+	}
+	if want := [][]string{{"a", "c", "b"}, {"e"}}; !reflect.DeepEqual(treeErr.Cycles, want) {
+		t.Errorf("Cycles = %v, want %v", treeErr.Cycles, want)
+	}
+
+	if got, want := projectIDs(tree.Roots()), []string{"a", "d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Roots() = %v, want %v", got, want)
+	}
+	if got := tree.Path("c"); got != "A/B/C" {
+		t.Errorf("Path() = %q, want %q", got, "A/B/C")
+	}
+
+	if got := ChildProjectIDs(projects)[""]; !reflect.DeepEqual(got, []string{"a", "d", "e"}) {
+		t.Errorf("ChildProjectIDs() = %v", got)
+	}
+}
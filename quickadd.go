@@ -0,0 +1,78 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// QuickAddOptions are the optional parameters for QuickAddTask.
+type QuickAddOptions struct {
+	// Note is added as a comment to the task.
+	Note string
+
+	// Reminder is a date string, e.g., "tomorrow 2pm", for a reminder.
+	Reminder string
+
+	// AutoReminder adds the default reminder if the task has a due time.
+	AutoReminder bool
+}
+
+// QuickAddTask creates a task from text using the same parsing as the
+// Todoist quick add, e.g., "Call vendor tomorrow 3pm #Ops @urgent p1".
+// The returned task includes the project, labels, priority, and due date
+// resolved from text.
+//
+// See https://developer.todoist.com/sync/v9/#quick-add-an-item
+func (c *TodoistClient) QuickAddTask(ctx context.Context, text string, opts *QuickAddOptions) (Task, error) {
+	if text == "" {
+		return Task{}, fmt.Errorf("empty quick add text")
+	}
+
+	form := url.Values{}
+	form.Set("text", text)
+
+	if opts != nil {
+		if opts.Note != "" {
+			form.Set("note", opts.Note)
+		}
+
+		if opts.Reminder != "" {
+			form.Set("reminder", opts.Reminder)
+		}
+
+		if opts.AutoReminder {
+			form.Set("auto_reminder", "true")
+		}
+	}
+
+	body, err := c.SyncPost(ctx, "/quick/add", form)
+	if err != nil {
+		return Task{}, err
+	}
+
+	var item SyncItem
+	err = json.Unmarshal(body, &item)
+	if err != nil {
+		return Task{}, err
+	}
+
+	return item.Task(), nil
+}
@@ -0,0 +1,69 @@
+package tdapi
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQuickAddTask(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/sync/v9/quick/add" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		want := map[string]string{
+			"text":          "Call vendor tomorrow 3pm #Ops @urgent p1",
+			"note":          "see email",
+			"auto_reminder": "true",
+			"reminder":      "",
+		}
+		for key, value := range want {
+			if got := r.FormValue(key); got != value {
+				t.Errorf("Form %s = %q, want %q", key, got, value)
+			}
+		}
+
+		body, err := os.ReadFile("testdata/quick_add.json")
+		if err != nil {
+			t.Errorf("Cannot read file: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write(body)
+	}))
+
+	task, err := api.QuickAddTask(context.Background(),
+		"Call vendor tomorrow 3pm #Ops @urgent p1",
+		&QuickAddOptions{Note: "see email", AutoReminder: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := Task{
+		ID:        "6X7rM8997g3RQmvh",
+		ProjectID: "6Jf8VQXxpwv56VQ7",
+		Content:   "Call vendor",
+		Labels:    []string{"urgent"},
+		Order:     3,
+		Priority:  4,
+		Due: &TaskDue{
+			String: "tomorrow 3pm",
+			Date:   "2023-03-13T15:00:00",
+			Lang:   "en",
+		},
+		CreatedAt: time.Date(2023, 3, 12, 10, 0, 0, 0, time.UTC),
+		CreatorID: "2671355",
+	}
+
+	if !reflect.DeepEqual(task, want) {
+		t.Errorf("Task does not match.\n Got: %+v\nWant: %+v\n", task, want)
+	}
+
+	if task.Due.Kind() != DueFloating {
+		t.Errorf("Due.Kind() = %v, want %v", task.Due.Kind(), DueFloating)
+	}
+}
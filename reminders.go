@@ -0,0 +1,235 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Reminder types.
+const (
+	ReminderRelative = "relative"
+	ReminderAbsolute = "absolute"
+	ReminderLocation = "location"
+)
+
+// Location reminder triggers.
+const (
+	ReminderOnEnter = "on_enter"
+	ReminderOnLeave = "on_leave"
+)
+
+// A Reminder is a reminder for a task.
+// See https://developer.todoist.com/sync/v9/#reminders
+type Reminder struct {
+	ID        string `json:"id"`
+	NotifyUID string `json:"notify_uid,omitempty"`
+	ItemID    string `json:"item_id"`
+
+	// Type is one of ReminderRelative, ReminderAbsolute, or ReminderLocation.
+	Type string `json:"type"`
+
+	// Due is the date and time of an absolute reminder.
+	Due *TaskDue `json:"due,omitempty"`
+
+	// MinuteOffset is the minutes before the task is due for a relative
+	// reminder.
+	MinuteOffset int `json:"minute_offset,omitempty"`
+
+	// Name, LocLat, LocLong, LocTrigger, and Radius describe a location
+	// reminder. LocTrigger is "on_enter" or "on_leave" and Radius is in
+	// meters.
+	Name       string `json:"name,omitempty"`
+	LocLat     string `json:"loc_lat,omitempty"`
+	LocLong    string `json:"loc_long,omitempty"`
+	LocTrigger string `json:"loc_trigger,omitempty"`
+	Radius     int    `json:"radius,omitempty"`
+
+	IsDeleted bool `json:"is_deleted"`
+}
+
+// A ReminderRequest contains the fields to add or update a reminder.
+// Empty fields are not sent, so they are not changed by an update.
+// See https://developer.todoist.com/sync/v9/#add-a-reminder
+type ReminderRequest struct {
+	// ItemID is the task for the reminder and is required to add one.
+	ItemID string `json:"item_id,omitempty"`
+
+	// Type is one of ReminderRelative, ReminderAbsolute, or ReminderLocation.
+	Type string `json:"type,omitempty"`
+
+	NotifyUID    string      `json:"notify_uid,omitempty"`
+	Due          *DueRequest `json:"due,omitempty"`
+	MinuteOffset *int        `json:"minute_offset,omitempty"`
+
+	Name       string `json:"name,omitempty"`
+	LocLat     string `json:"loc_lat,omitempty"`
+	LocLong    string `json:"loc_long,omitempty"`
+	LocTrigger string `json:"loc_trigger,omitempty"`
+	Radius     int    `json:"radius,omitempty"`
+}
+
+// RelativeReminder returns a request for a reminder before the task
+// with itemID is due. before is rounded down to whole minutes.
+func RelativeReminder(itemID string, before time.Duration) ReminderRequest {
+	minutes := int(before / time.Minute)
+
+	return ReminderRequest{
+		ItemID:       itemID,
+		Type:         ReminderRelative,
+		MinuteOffset: &minutes,
+	}
+}
+
+// AbsoluteReminder returns a request for a reminder at t for the task
+// with itemID.
+func AbsoluteReminder(itemID string, t time.Time) ReminderRequest {
+	return ReminderRequest{
+		ItemID: itemID,
+		Type:   ReminderAbsolute,
+		Due:    &DueRequest{Date: t.UTC().Format("2006-01-02T15:04:05Z")},
+	}
+}
+
+// GetReminders returns all reminders.
+func (c *TodoistClient) GetReminders(ctx context.Context) ([]Reminder, error) {
+	response, err := c.Sync(ctx, "", []string{ResourceReminders})
+	if err != nil {
+		return nil, err
+	}
+
+	reminders := make([]Reminder, 0, len(response.Reminders))
+	for _, reminder := range response.Reminders {
+		if !reminder.IsDeleted {
+			reminders = append(reminders, reminder)
+		}
+	}
+
+	return reminders, nil
+}
+
+// GetTaskReminders returns the reminders for the task with taskID.
+func (c *TodoistClient) GetTaskReminders(ctx context.Context, taskID string) ([]Reminder, error) {
+	if taskID == "" {
+		return nil, fmt.Errorf("empty task ID")
+	}
+
+	reminders, err := c.GetReminders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var taskReminders []Reminder
+	for _, reminder := range reminders {
+		if reminder.ItemID == taskID {
+			taskReminders = append(taskReminders, reminder)
+		}
+	}
+
+	return taskReminders, nil
+}
+
+// AddReminder adds a reminder, returning the added reminder.
+// ItemID and Type are required.
+func (c *TodoistClient) AddReminder(ctx context.Context, r ReminderRequest) (Reminder, error) {
+	if r.ItemID == "" {
+		return Reminder{}, fmt.Errorf("empty task ID")
+	}
+
+	switch r.Type {
+	case ReminderRelative:
+		if r.MinuteOffset == nil {
+			return Reminder{}, fmt.Errorf("relative reminder requires minute offset")
+		}
+	case ReminderAbsolute:
+		if r.Due == nil || (r.Due.Date == "" && r.Due.String == "") {
+			return Reminder{}, fmt.Errorf("absolute reminder requires due")
+		}
+	case ReminderLocation:
+		if r.LocLat == "" || r.LocLong == "" {
+			return Reminder{}, fmt.Errorf("location reminder requires latitude and longitude")
+		}
+	default:
+		return Reminder{}, fmt.Errorf("invalid reminder type %q", r.Type)
+	}
+
+	cmd := NewCommand("reminder_add", r)
+	cmd.TempID = newUUID()
+
+	response, err := c.executeCommand(ctx, cmd)
+	if err != nil {
+		return Reminder{}, err
+	}
+
+	reminder := Reminder{
+		ID:         response.TempIDMapping[cmd.TempID],
+		NotifyUID:  r.NotifyUID,
+		ItemID:     r.ItemID,
+		Type:       r.Type,
+		Name:       r.Name,
+		LocLat:     r.LocLat,
+		LocLong:    r.LocLong,
+		LocTrigger: r.LocTrigger,
+		Radius:     r.Radius,
+	}
+	if r.MinuteOffset != nil {
+		reminder.MinuteOffset = *r.MinuteOffset
+	}
+	if r.Due != nil {
+		reminder.Due = &TaskDue{
+			String:   r.Due.String,
+			Date:     r.Due.Date,
+			Timezone: r.Due.Timezone,
+			Lang:     r.Due.Lang,
+		}
+	}
+
+	return reminder, nil
+}
+
+// UpdateReminder updates the reminder with id.
+func (c *TodoistClient) UpdateReminder(ctx context.Context, id string, r ReminderRequest) error {
+	if id == "" {
+		return fmt.Errorf("empty reminder ID")
+	}
+
+	// the task of a reminder cannot be changed
+	r.ItemID = ""
+
+	args := struct {
+		ID string `json:"id"`
+		ReminderRequest
+	}{id, r}
+
+	_, err := c.executeCommand(ctx, NewCommand("reminder_update", args))
+
+	return err
+}
+
+// DeleteReminder deletes the reminder with id.
+func (c *TodoistClient) DeleteReminder(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("empty reminder ID")
+	}
+
+	_, err := c.executeCommand(ctx,
+		NewCommand("reminder_delete", map[string]string{"id": id}))
+
+	return err
+}
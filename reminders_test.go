@@ -0,0 +1,146 @@
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetTaskReminders(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.FormValue("resource_types"); got != `["reminders"]` {
+			t.Errorf("resource_types = %q, want %q", got, `["reminders"]`)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_token": "token",
+			"full_sync":  true,
+			"reminders": []Reminder{
+				{ID: "1", ItemID: "100", Type: ReminderRelative, MinuteOffset: 30},
+				{ID: "2", ItemID: "200", Type: ReminderRelative, MinuteOffset: 15},
+				{ID: "3", ItemID: "100", Type: ReminderAbsolute, IsDeleted: true},
+				{ID: "4", ItemID: "100", Type: ReminderLocation, Name: "Office"},
+			},
+		})
+	}))
+
+	reminders, err := api.GetTaskReminders(context.Background(), "100")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, reminder := range reminders {
+		ids = append(ids, reminder.ID)
+	}
+	if want := []string{"1", "4"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Reminder IDs = %v, want %v", ids, want)
+	}
+}
+
+func TestReminderCommands(t *testing.T) {
+	var commands []Command
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var cmds []Command
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &cmds); err != nil {
+			t.Errorf("Cannot decode commands: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		commands = append(commands, cmds...)
+
+		status := make(map[string]string)
+		mapping := make(map[string]string)
+		for _, cmd := range cmds {
+			status[cmd.UUID] = "ok"
+			if cmd.TempID != "" {
+				mapping[cmd.TempID] = "2992683215"
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_status":     status,
+			"temp_id_mapping": mapping,
+		})
+	}))
+	ctx := context.Background()
+
+	reminder, err := api.AddReminder(ctx, RelativeReminder("100", 30*time.Minute))
+	if err != nil {
+		t.Fatalf("AddReminder: %v", err)
+	}
+	if reminder.ID != "2992683215" || reminder.ItemID != "100" || reminder.MinuteOffset != 30 {
+		t.Errorf("Unexpected reminder: %+v", reminder)
+	}
+
+	at := time.Date(2024, 3, 10, 9, 0, 0, 0, mustLoadLocation(t, "America/New_York"))
+	reminder, err = api.AddReminder(ctx, AbsoluteReminder("100", at))
+	if err != nil {
+		t.Fatalf("AddReminder: %v", err)
+	}
+	if reminder.Due == nil || reminder.Due.Date != "2024-03-10T13:00:00Z" {
+		t.Errorf("Unexpected reminder due: %+v", reminder.Due)
+	}
+
+	location := ReminderRequest{
+		ItemID: "100", Type: ReminderLocation, Name: "Office",
+		LocLat: "40.7", LocLong: "-74.0", LocTrigger: ReminderOnEnter, Radius: 100,
+	}
+	if _, err := api.AddReminder(ctx, location); err != nil {
+		t.Fatalf("AddReminder: %v", err)
+	}
+
+	update := RelativeReminder("100", time.Hour)
+	if err := api.UpdateReminder(ctx, "2992683215", update); err != nil {
+		t.Fatalf("UpdateReminder: %v", err)
+	}
+	if err := api.DeleteReminder(ctx, "2992683215"); err != nil {
+		t.Fatalf("DeleteReminder: %v", err)
+	}
+
+	tests := []struct {
+		Type string
+		Args string
+	}{
+		{"reminder_add", `{"item_id":"100","minute_offset":30,"type":"relative"}`},
+		{"reminder_add", `{"due":{"date":"2024-03-10T13:00:00Z"},"item_id":"100","type":"absolute"}`},
+		{"reminder_add", `{"item_id":"100","loc_lat":"40.7","loc_long":"-74.0","loc_trigger":"on_enter","name":"Office","radius":100,"type":"location"}`},
+		{"reminder_update", `{"id":"2992683215","minute_offset":60,"type":"relative"}`},
+		{"reminder_delete", `{"id":"2992683215"}`},
+	}
+	if len(commands) != len(tests) {
+		t.Fatalf("Got %d commands, want %d", len(commands), len(tests))
+	}
+	for n, tc := range tests {
+		args, _ := json.Marshal(commands[n].Args)
+		if commands[n].Type != tc.Type || string(args) != tc.Args {
+			t.Errorf("Command %d = %s %s, want %s %s",
+				n, commands[n].Type, args, tc.Type, tc.Args)
+		}
+	}
+}
+
+func TestAddReminderValidation(t *testing.T) {
+	api := &TodoistClient{}
+
+	tests := []struct {
+		name string
+		r    ReminderRequest
+	}{
+		{"no task", ReminderRequest{Type: ReminderRelative}},
+		{"no type", ReminderRequest{ItemID: "100"}},
+		{"relative without offset", ReminderRequest{ItemID: "100", Type: ReminderRelative}},
+		{"absolute without due", ReminderRequest{ItemID: "100", Type: ReminderAbsolute}},
+		{"absolute with empty due", ReminderRequest{ItemID: "100", Type: ReminderAbsolute, Due: &DueRequest{}}},
+		{"location without coordinates", ReminderRequest{ItemID: "100", Type: ReminderLocation}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := api.AddReminder(context.Background(), tc.r); err == nil {
+				t.Errorf("Expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,572 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// replicaResources are the resource types kept by a Replica.
+var replicaResources = []string{
+	ResourceItems,
+	ResourceProjects,
+	ResourceSections,
+	ResourceLabels,
+	ResourceUser,
+}
+
+// ChangeKind describes how an object changed in a Replica.
+type ChangeKind int
+
+const (
+	ChangeAdded ChangeKind = iota
+	ChangeUpdated
+	ChangeDeleted
+	ChangeArchived
+	ChangeCompleted
+)
+
+// String returns a string representation of the ChangeKind.
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeUpdated:
+		return "updated"
+	case ChangeDeleted:
+		return "deleted"
+	case ChangeArchived:
+		return "archived"
+	case ChangeCompleted:
+		return "completed"
+	}
+	return "unknown"
+}
+
+// A Change is a change to an object in a Replica.
+type Change struct {
+	// Resource is the resource type of the object, e.g., ResourceItems.
+	Resource string
+	ID       string
+	Kind     ChangeKind
+}
+
+// A ChangeSet is the changes applied to a Replica by a sync.
+type ChangeSet struct {
+	// FullSync is true if the replica was replaced by a full sync.
+	FullSync bool
+	Changes  []Change
+}
+
+// A Replica is an in-memory copy of the account, kept current by
+// incremental syncs. It holds active tasks, projects, sections, and
+// personal labels. Completed, deleted, and archived objects are removed,
+// along with the tasks and sections of an archived project and the tasks
+// of an archived section.
+//
+// The read accessors are safe for concurrent use and return copies.
+type Replica struct {
+	syncer *Syncer
+
+	// syncMu serializes syncs
+	syncMu sync.Mutex
+
+	mu       sync.RWMutex
+	synced   bool
+	items    map[string]SyncItem
+	projects map[string]SyncProject
+	sections map[string]SyncSection
+	labels   map[string]SyncLabel
+	user     *User
+
+	byProject  map[string][]string
+	bySection  map[string][]string
+	byLabel    map[string][]string
+	byAssignee map[string][]string
+
+	subMu       sync.Mutex
+	nextSubID   int
+	subscribers map[int]func(ChangeSet)
+}
+
+// NewReplica returns an empty Replica that syncs using client.
+// Call Sync or Run to populate it.
+func NewReplica(client *TodoistClient) *Replica {
+	return &Replica{
+		syncer:      NewSyncer(client, nil),
+		items:       make(map[string]SyncItem),
+		projects:    make(map[string]SyncProject),
+		sections:    make(map[string]SyncSection),
+		labels:      make(map[string]SyncLabel),
+		subscribers: make(map[int]func(ChangeSet)),
+	}
+}
+
+// Subscribe calls fn with the changes of each sync that changes the
+// replica. fn is called after the changes are applied, from the goroutine
+// that called Sync. The returned function cancels the subscription.
+func (r *Replica) Subscribe(fn func(ChangeSet)) (unsubscribe func()) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	id := r.nextSubID
+	r.nextSubID++
+	r.subscribers[id] = fn
+
+	return func() {
+		r.subMu.Lock()
+		defer r.subMu.Unlock()
+		delete(r.subscribers, id)
+	}
+}
+
+// notify calls the subscribers with changes.
+func (r *Replica) notify(changes ChangeSet) {
+	r.subMu.Lock()
+	subscribers := make([]func(ChangeSet), 0, len(r.subscribers))
+	for _, fn := range r.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	r.subMu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(changes)
+	}
+}
+
+// Sync does a full sync the first time it is called, and an incremental
+// sync after that, applying the changes to the replica and notifying
+// subscribers. The returned ChangeSet reports the changes.
+func (r *Replica) Sync(ctx context.Context) (ChangeSet, error) {
+	r.syncMu.Lock()
+	defer r.syncMu.Unlock()
+
+	r.mu.RLock()
+	synced := r.synced
+	r.mu.RUnlock()
+
+	var response *SyncResponse
+	var err error
+	if synced {
+		response, err = r.syncer.Sync(ctx, replicaResources...)
+	} else {
+		response, err = r.syncer.FullSync(ctx, replicaResources...)
+	}
+	if err != nil {
+		return ChangeSet{}, err
+	}
+
+	changes := r.apply(response)
+	if len(changes.Changes) > 0 || changes.FullSync {
+		r.notify(changes)
+	}
+
+	return changes, nil
+}
+
+// Run syncs the replica every interval until ctx is done or a sync fails,
+// returning the error.
+func (r *Replica) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := r.Sync(ctx)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// changeKind returns the kind of change for an object that was known, or
+// not, before it was synced.
+func changeKind(known bool) ChangeKind {
+	if known {
+		return ChangeUpdated
+	}
+	return ChangeAdded
+}
+
+// apply applies the sync response to the replica, returning the changes.
+func (r *Replica) apply(response *SyncResponse) ChangeSet {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes := ChangeSet{FullSync: response.FullSync}
+	add := func(resource string, id string, kind ChangeKind) {
+		changes.Changes = append(changes.Changes, Change{resource, id, kind})
+	}
+
+	previousItems := r.items
+	previousProjects := r.projects
+	previousSections := r.sections
+	previousLabels := r.labels
+
+	if response.FullSync {
+		r.items = make(map[string]SyncItem)
+		r.projects = make(map[string]SyncProject)
+		r.sections = make(map[string]SyncSection)
+		r.labels = make(map[string]SyncLabel)
+	}
+
+	// how removed projects and sections went, for their contents
+	removed := map[string]map[string]ChangeKind{
+		ResourceProjects: make(map[string]ChangeKind),
+		ResourceSections: make(map[string]ChangeKind),
+	}
+
+	for _, project := range response.Projects {
+		_, known := previousProjects[project.ID]
+		switch {
+		case project.IsDeleted:
+			delete(r.projects, project.ID)
+			removed[ResourceProjects][project.ID] = ChangeDeleted
+			if known {
+				add(ResourceProjects, project.ID, ChangeDeleted)
+			}
+		case project.IsArchived:
+			delete(r.projects, project.ID)
+			removed[ResourceProjects][project.ID] = ChangeArchived
+			if known {
+				add(ResourceProjects, project.ID, ChangeArchived)
+			}
+		default:
+			r.projects[project.ID] = project
+			add(ResourceProjects, project.ID, changeKind(known))
+		}
+	}
+
+	for _, section := range response.Sections {
+		_, known := previousSections[section.ID]
+		switch {
+		case section.IsDeleted:
+			delete(r.sections, section.ID)
+			removed[ResourceSections][section.ID] = ChangeDeleted
+			if known {
+				add(ResourceSections, section.ID, ChangeDeleted)
+			}
+		case section.IsArchived:
+			delete(r.sections, section.ID)
+			removed[ResourceSections][section.ID] = ChangeArchived
+			if known {
+				add(ResourceSections, section.ID, ChangeArchived)
+			}
+		default:
+			r.sections[section.ID] = section
+			add(ResourceSections, section.ID, changeKind(known))
+		}
+	}
+
+	for _, label := range response.Labels {
+		_, known := previousLabels[label.ID]
+		if label.IsDeleted {
+			delete(r.labels, label.ID)
+			if known {
+				add(ResourceLabels, label.ID, ChangeDeleted)
+			}
+			continue
+		}
+		r.labels[label.ID] = label
+		add(ResourceLabels, label.ID, changeKind(known))
+	}
+
+	for _, item := range response.Items {
+		_, known := previousItems[item.ID]
+		switch {
+		case item.IsDeleted:
+			delete(r.items, item.ID)
+			if known {
+				add(ResourceItems, item.ID, ChangeDeleted)
+			}
+		case item.Checked:
+			delete(r.items, item.ID)
+			if known {
+				add(ResourceItems, item.ID, ChangeCompleted)
+			}
+		default:
+			r.items[item.ID] = item
+			add(ResourceItems, item.ID, changeKind(known))
+		}
+	}
+
+	// removedKind returns how the parent with id was removed, which is
+	// deleted if the parent was not reported in this sync
+	removedKind := func(resource string, id string) ChangeKind {
+		if kind, ok := removed[resource][id]; ok {
+			return kind
+		}
+		return ChangeDeleted
+	}
+
+	// remove sections and tasks of projects and sections that are gone,
+	// with the same kind of change as their parent, which a full sync
+	// reports below as deleted
+	for id, section := range r.sections {
+		if _, ok := r.projects[section.ProjectID]; !ok {
+			delete(r.sections, id)
+			kind := removedKind(ResourceProjects, section.ProjectID)
+			removed[ResourceSections][id] = kind
+			if !response.FullSync {
+				add(ResourceSections, id, kind)
+			}
+		}
+	}
+	for id, item := range r.items {
+		_, projectOK := r.projects[item.ProjectID]
+		_, sectionOK := r.sections[item.SectionID]
+
+		var kind ChangeKind
+		switch {
+		case !projectOK:
+			kind = removedKind(ResourceProjects, item.ProjectID)
+		case item.SectionID != "" && !sectionOK:
+			kind = removedKind(ResourceSections, item.SectionID)
+		default:
+			continue
+		}
+
+		delete(r.items, id)
+		if !response.FullSync {
+			add(ResourceItems, id, kind)
+		}
+	}
+
+	// a full sync removes objects that are no longer returned
+	if response.FullSync {
+		for id := range previousProjects {
+			if _, ok := r.projects[id]; !ok {
+				add(ResourceProjects, id, ChangeDeleted)
+			}
+		}
+		for id := range previousSections {
+			if _, ok := r.sections[id]; !ok {
+				add(ResourceSections, id, ChangeDeleted)
+			}
+		}
+		for id := range previousLabels {
+			if _, ok := r.labels[id]; !ok {
+				add(ResourceLabels, id, ChangeDeleted)
+			}
+		}
+		for id := range previousItems {
+			if _, ok := r.items[id]; !ok {
+				add(ResourceItems, id, ChangeDeleted)
+			}
+		}
+	}
+
+	if response.User != nil {
+		user := *response.User
+		r.user = &user
+	}
+
+	r.synced = true
+	r.reindex()
+
+	return changes
+}
+
+// reindex rebuilds the task indexes. The caller must hold the write lock.
+func (r *Replica) reindex() {
+	r.byProject = make(map[string][]string)
+	r.bySection = make(map[string][]string)
+	r.byLabel = make(map[string][]string)
+	r.byAssignee = make(map[string][]string)
+
+	for id, item := range r.items {
+		r.byProject[item.ProjectID] = append(r.byProject[item.ProjectID], id)
+		if item.SectionID != "" {
+			r.bySection[item.SectionID] = append(r.bySection[item.SectionID], id)
+		}
+		for _, label := range item.Labels {
+			r.byLabel[label] = append(r.byLabel[label], id)
+		}
+		if item.ResponsibleUID != "" {
+			r.byAssignee[item.ResponsibleUID] =
+				append(r.byAssignee[item.ResponsibleUID], id)
+		}
+	}
+}
+
+// tasks returns the tasks for ids, sorted by Order then ID.
+// The caller must hold the read lock.
+func (r *Replica) tasks(ids []string) []Task {
+	tasks := make([]Task, 0, len(ids))
+	for _, id := range ids {
+		tasks = append(tasks, r.items[id].Task())
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Order != tasks[j].Order {
+			return tasks[i].Order < tasks[j].Order
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+
+	return tasks
+}
+
+// Tasks returns all active tasks, sorted by Order then ID.
+func (r *Replica) Tasks() []Task {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]string, 0, len(r.items))
+	for id := range r.items {
+		ids = append(ids, id)
+	}
+
+	return r.tasks(ids)
+}
+
+// Task returns the active task with id and whether it was found.
+func (r *Replica) Task(id string) (Task, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return Task{}, false
+	}
+	return item.Task(), true
+}
+
+// TasksByProject returns the active tasks in the project with projectID.
+func (r *Replica) TasksByProject(projectID string) []Task {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.tasks(r.byProject[projectID])
+}
+
+// TasksBySection returns the active tasks in the section with sectionID.
+func (r *Replica) TasksBySection(sectionID string) []Task {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.tasks(r.bySection[sectionID])
+}
+
+// TasksByLabel returns the active tasks with the label name.
+func (r *Replica) TasksByLabel(name string) []Task {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.tasks(r.byLabel[name])
+}
+
+// TasksByAssignee returns the active tasks assigned to the user with userID.
+func (r *Replica) TasksByAssignee(userID string) []Task {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.tasks(r.byAssignee[userID])
+}
+
+// Projects returns the active projects in no particular order.
+// Use NewProjectTree to order them.
+func (r *Replica) Projects() []Project {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	projects := make([]Project, 0, len(r.projects))
+	for _, project := range r.projects {
+		projects = append(projects, project.Project())
+	}
+
+	return projects
+}
+
+// Project returns the active project with id and whether it was found.
+func (r *Replica) Project(id string) (Project, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	project, ok := r.projects[id]
+	if !ok {
+		return Project{}, false
+	}
+	return project.Project(), true
+}
+
+// Sections returns the active sections of the project with projectID, or
+// of all projects if projectID is empty, sorted by Order then ID.
+func (r *Replica) Sections(projectID string) []Section {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var sections []Section
+	for _, section := range r.sections {
+		if projectID == "" || section.ProjectID == projectID {
+			sections = append(sections, section.Section())
+		}
+	}
+
+	sort.Slice(sections, func(i, j int) bool {
+		if sections[i].Order != sections[j].Order {
+			return sections[i].Order < sections[j].Order
+		}
+		return sections[i].ID < sections[j].ID
+	})
+
+	return sections
+}
+
+// Labels returns the personal labels sorted by Order then ID.
+func (r *Replica) Labels() []PersonalLabel {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	labels := make([]PersonalLabel, 0, len(r.labels))
+	for _, label := range r.labels {
+		labels = append(labels, label.PersonalLabel())
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].Order != labels[j].Order {
+			return labels[i].Order < labels[j].Order
+		}
+		return labels[i].ID < labels[j].ID
+	})
+
+	return labels
+}
+
+// User returns the user and whether the replica has been synced.
+func (r *Replica) User() (User, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.user == nil {
+		return User{}, false
+	}
+
+	user := *r.user
+	if user.DaysOff != nil {
+		user.DaysOff = append([]int{}, user.DaysOff...)
+	}
+	return user, true
+}
@@ -0,0 +1,225 @@
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// changeStrings returns the changes as sorted "resource id kind" strings.
+func changeStrings(changes ChangeSet) []string {
+	var s []string
+	for _, change := range changes.Changes {
+		s = append(s, change.Resource+" "+change.ID+" "+change.Kind.String())
+	}
+	sort.Strings(s)
+	return s
+}
+
+func TestReplica(t *testing.T) {
+	responses := []map[string]interface{}{
+		{
+			"sync_token": "token-1",
+			"full_sync":  true,
+			"projects": []map[string]interface{}{
+				{"id": "p1", "name": "Work"},
+				{"id": "p2", "name": "Client"},
+			},
+			"sections": []map[string]interface{}{
+				{"id": "s1", "project_id": "p1", "name": "Next"},
+			},
+			"labels": []map[string]interface{}{
+				{"id": "l1", "name": "urgent"},
+			},
+			"items": []map[string]interface{}{
+				{"id": "i1", "project_id": "p1", "section_id": "s1", "labels": []string{"urgent"}, "responsible_uid": "u1", "child_order": 2},
+				{"id": "i2", "project_id": "p2"},
+			},
+			"user": map[string]interface{}{"id": "u1", "full_name": "Example User"},
+		},
+		{
+			"sync_token": "token-2",
+			"projects":   []map[string]interface{}{{"id": "p2", "name": "Client", "is_archived": true}},
+			"items": []map[string]interface{}{
+				{"id": "i1", "project_id": "p1", "section_id": "s1", "content": "Updated", "child_order": 2},
+				{"id": "i3", "project_id": "p1", "labels": []string{"urgent"}, "child_order": 1},
+			},
+		},
+		{
+			"sync_token": "token-3",
+			"sections":   []map[string]interface{}{{"id": "s1", "project_id": "p1", "is_deleted": true}},
+			"items":      []map[string]interface{}{{"id": "i3", "project_id": "p1", "checked": true}},
+		},
+	}
+
+	var tokens []string
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.FormValue("sync_token"))
+		json.NewEncoder(w).Encode(responses[len(tokens)-1])
+	}))
+
+	replica := NewReplica(api)
+
+	var notified []ChangeSet
+	unsubscribe := replica.Subscribe(func(changes ChangeSet) {
+		notified = append(notified, changes)
+	})
+
+	ctx := context.Background()
+
+	changes, err := replica.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !changes.FullSync || len(changes.Changes) != 6 {
+		t.Errorf("First sync changes = %v", changeStrings(changes))
+	}
+	if got := taskIDs(replica.TasksByAssignee("u1")); !reflect.DeepEqual(got, []string{"i1"}) {
+		t.Errorf("TasksByAssignee() = %v", got)
+	}
+	if user, ok := replica.User(); !ok || user.FullName != "Example User" {
+		t.Errorf("User() = %+v, %v", user, ok)
+	}
+
+	changes, err = replica.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	wantChanges := []string{
+		"items i1 updated",
+		"items i2 archived",
+		"items i3 added",
+		"projects p2 archived",
+	}
+	if got := changeStrings(changes); !reflect.DeepEqual(got, wantChanges) {
+		t.Errorf("Second sync changes.\n Got: %v\nWant: %v", got, wantChanges)
+	}
+	if got := taskIDs(replica.TasksByLabel("urgent")); !reflect.DeepEqual(got, []string{"i3"}) {
+		t.Errorf("TasksByLabel() = %v", got)
+	}
+	if got := taskIDs(replica.TasksByProject("p1")); !reflect.DeepEqual(got, []string{"i3", "i1"}) {
+		t.Errorf("TasksByProject() = %v", got)
+	}
+	if _, ok := replica.Project("p2"); ok {
+		t.Errorf("Archived project still in replica")
+	}
+
+	unsubscribe()
+
+	changes, err = replica.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	wantChanges = []string{
+		"items i1 deleted",
+		"items i3 completed",
+		"sections s1 deleted",
+	}
+	if got := changeStrings(changes); !reflect.DeepEqual(got, wantChanges) {
+		t.Errorf("Third sync changes.\n Got: %v\nWant: %v", got, wantChanges)
+	}
+	if got := replica.Tasks(); len(got) != 0 {
+		t.Errorf("Tasks() = %v, want none", taskIDs(got))
+	}
+
+	if len(notified) != 2 {
+		t.Errorf("Got %d notifications, want 2", len(notified))
+	}
+	if want := []string{"*", "token-1", "token-2"}; !reflect.DeepEqual(tokens, want) {
+		t.Errorf("Tokens = %v, want %v", tokens, want)
+	}
+}
+
+func TestReplicaConcurrentReads(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_token": "token",
+			"full_sync":  r.FormValue("sync_token") == "*",
+			"projects":   []map[string]interface{}{{"id": "p1"}},
+			"items":      []map[string]interface{}{{"id": "i1", "project_id": "p1"}},
+		})
+	}))
+
+	replica := NewReplica(api)
+
+	var wg sync.WaitGroup
+	for n := 0; n < 4; n++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			replica.Sync(context.Background())
+		}()
+		go func() {
+			defer wg.Done()
+			replica.Tasks()
+			replica.TasksByProject("p1")
+			replica.Projects()
+		}()
+	}
+	wg.Wait()
+
+	if got := taskIDs(replica.Tasks()); !reflect.DeepEqual(got, []string{"i1"}) {
+		t.Errorf("Tasks() = %v", got)
+	}
+}
+
+func TestReplicaReturnsCopies(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_token": "token",
+			"full_sync":  true,
+			"projects": []map[string]interface{}{
+				{"id": "p1"},
+				{"id": "p2", "parent_id": "p1"},
+			},
+			"items": []map[string]interface{}{
+				{
+					"id": "i1", "project_id": "p2", "labels": []string{"urgent"},
+					"due":      map[string]interface{}{"date": "2024-01-15"},
+					"duration": map[string]interface{}{"amount": 15, "unit": "minute"},
+					"deadline": map[string]interface{}{"date": "2024-01-20"},
+				},
+			},
+			"user": map[string]interface{}{"id": "u1", "days_off": []int{6, 7}},
+		})
+	}))
+
+	replica := NewReplica(api)
+	if _, err := replica.Sync(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want, _ := replica.Task("i1")
+
+	task, _ := replica.Task("i1")
+	task.Labels[0] = "changed"
+	task.Due.Date = "2000-01-01"
+	task.Duration.Amount = 0
+	task.Deadline.Date = "2000-01-01"
+
+	tasks := replica.TasksByLabel("urgent")
+	tasks[0].Labels[0] = "changed"
+
+	if got, _ := replica.Task("i1"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Task() changed.\n Got: %+v\nWant: %+v", got, want)
+	}
+	if got := taskIDs(replica.TasksByLabel("urgent")); !reflect.DeepEqual(got, []string{"i1"}) {
+		t.Errorf("TasksByLabel() = %v", got)
+	}
+
+	project, _ := replica.Project("p2")
+	*project.ParentID = "changed"
+	if got, _ := replica.Project("p2"); got.ParentID == nil || *got.ParentID != "p1" {
+		t.Errorf("Project() ParentID = %v, want p1", got.ParentID)
+	}
+
+	user, _ := replica.User()
+	user.DaysOff[0] = 1
+	if got, _ := replica.User(); !reflect.DeepEqual(got.DaysOff, []int{6, 7}) {
+		t.Errorf("User() DaysOff = %v, want [6 7]", got.DaysOff)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+// NoSectionName is the name of the synthetic section for tasks without
+// a section, as returned by GroupTasksBySection.
+const NoSectionName = "(No section)"
+
+// A Section represents a Todoist section within a project.
+// See https://developer.todoist.com/rest/v2/?shell#sections for more details.
+type Section struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	Order     int    `json:"order"`
+	Name      string `json:"name"`
+}
+
+// A CreateSectionRequest contains the fields to create a section.
+// See https://developer.todoist.com/rest/v2/?shell#create-a-new-section
+type CreateSectionRequest struct {
+	Name      string `json:"name"`
+	ProjectID string `json:"project_id"`
+	Order     int    `json:"order,omitempty"`
+}
+
+// GetSections returns all sections for the project with projectID, or all
+// sections if projectID is empty.
+func (c *TodoistClient) GetSections(projectID string) (response []Section, err error) {
+	var body []byte
+
+	query := url.Values{}
+	if projectID != "" {
+		query.Set("project_id", projectID)
+	}
+
+	body, err = c.Get("/sections", query)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, &response)
+
+	return response, err
+}
+
+// GetSection returns a section by ID.
+func (c *TodoistClient) GetSection(id string) (response Section, err error) {
+	var body []byte
+
+	if id == "" {
+		return response, fmt.Errorf("empty section ID")
+	}
+
+	body, err = c.Get("/sections/"+id, nil)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, &response)
+
+	return response, err
+}
+
+// CreateSection creates a new section, returning the created section.
+func (c *TodoistClient) CreateSection(r CreateSectionRequest) (response Section, err error) {
+	var body []byte
+
+	if r.Name == "" || r.ProjectID == "" {
+		return response, fmt.Errorf("section name and project ID are required")
+	}
+
+	body, err = c.postJSON("/sections", r)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, &response)
+
+	return response, err
+}
+
+// UpdateSection renames the section with id, returning the updated section.
+func (c *TodoistClient) UpdateSection(id string, name string) (response Section, err error) {
+	var body []byte
+
+	if id == "" {
+		return response, fmt.Errorf("empty section ID")
+	}
+
+	body, err = c.postJSON("/sections/"+id, map[string]string{"name": name})
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, &response)
+
+	return response, err
+}
+
+// DeleteSection deletes the section with id, along with all of its tasks.
+func (c *TodoistClient) DeleteSection(id string) error {
+	if id == "" {
+		return fmt.Errorf("empty section ID")
+	}
+
+	_, err := c.Delete("/sections/"+id, nil)
+
+	return err
+}
+
+// GroupTasksBySectionID groups Tasks by their Section ID.
+// Tasks without a section are grouped under an empty Section ID.
+func GroupTasksBySectionID(tasks []Task) map[string][]Task {
+	groups := make(map[string][]Task)
+	for _, task := range tasks {
+		groups[task.SectionID] = append(groups[task.SectionID], task)
+	}
+	return groups
+}
+
+// A SectionGroup is a section and its tasks.
+type SectionGroup struct {
+	Section Section
+	Tasks   []Task
+}
+
+// GroupTasksBySection groups tasks by section, with sections and tasks in
+// Order. The first group is a synthetic section, with an empty ID and
+// NoSectionName, for tasks without a section or with a section that is
+// not in sections. Groups are returned for sections without tasks.
+func GroupTasksBySection(sections []Section, tasks []Task) []SectionGroup {
+	sorted := append([]Section(nil), sections...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Order != sorted[j].Order {
+			return sorted[i].Order < sorted[j].Order
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	groups := make([]SectionGroup, 0, len(sorted)+1)
+	groups = append(groups, SectionGroup{Section: Section{Name: NoSectionName}})
+
+	groupBySectionID := make(map[string]int, len(sorted))
+	for _, section := range sorted {
+		groupBySectionID[section.ID] = len(groups)
+		groups = append(groups, SectionGroup{Section: section})
+	}
+
+	for _, task := range tasks {
+		n := groupBySectionID[task.SectionID]
+		groups[n].Tasks = append(groups[n].Tasks, task)
+	}
+
+	for _, group := range groups {
+		tasks := group.Tasks
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return tasks[i].Order < tasks[j].Order
+		})
+	}
+
+	return groups
+}
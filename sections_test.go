@@ -0,0 +1,211 @@
+package tdapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetSections(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/v2/sections" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("project_id"); got != "2203306141" {
+			t.Errorf("project_id = %q, want %q", got, "2203306141")
+		}
+		http.ServeFile(w, r, "testdata/sections.json")
+	}))
+
+	sections, err := api.GetSections("2203306141")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedSections := []Section{
+		{ID: "7025", ProjectID: "2203306141", Order: 2, Name: "Groceries"},
+		{ID: "7026", ProjectID: "2203306141", Order: 1, Name: "Errands"},
+	}
+
+	if !reflect.DeepEqual(sections, expectedSections) {
+		t.Errorf("Sections do not match.\n Got: %+v\nWant: %+v\n", sections, expectedSections)
+	}
+}
+
+func TestGetSection(t *testing.T) {
+	api := newServerClient(t, requestHandler(t, http.MethodGet, "/rest/v2/sections/7025", nil,
+		http.StatusOK, `{"id":"7025","project_id":"2203306141","order":1,"name":"Groceries"}`))
+
+	got, err := api.GetSection("7025")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := Section{ID: "7025", ProjectID: "2203306141", Order: 1, Name: "Groceries"}
+	if got != want {
+		t.Errorf("Section does not match.\n Got: %+v\nWant: %+v", got, want)
+	}
+
+	if _, err := api.GetSection(""); err == nil {
+		t.Errorf("GetSection(\"\") did not return an error")
+	}
+}
+
+func TestCreateSection(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  CreateSectionRequest
+		wantBody map[string]interface{}
+		status   int
+		body     string
+		want     Section
+		wantErr  bool
+	}{
+		{
+			name:     "name and project",
+			request:  CreateSectionRequest{Name: "Groceries", ProjectID: "2203306141"},
+			wantBody: map[string]interface{}{"name": "Groceries", "project_id": "2203306141"},
+			status:   http.StatusOK,
+			body:     `{"id":"7025","project_id":"2203306141","order":1,"name":"Groceries"}`,
+			want:     Section{ID: "7025", ProjectID: "2203306141", Order: 1, Name: "Groceries"},
+		},
+		{
+			name:     "with order",
+			request:  CreateSectionRequest{Name: "Groceries", ProjectID: "2203306141", Order: 3},
+			wantBody: map[string]interface{}{"name": "Groceries", "project_id": "2203306141", "order": 3.0},
+			status:   http.StatusOK,
+			body:     `{"id":"7025","project_id":"2203306141","order":3,"name":"Groceries"}`,
+			want:     Section{ID: "7025", ProjectID: "2203306141", Order: 3, Name: "Groceries"},
+		},
+		{
+			name:     "server error",
+			request:  CreateSectionRequest{Name: "Groceries", ProjectID: "2203306141"},
+			wantBody: map[string]interface{}{"name": "Groceries", "project_id": "2203306141"},
+			status:   http.StatusBadRequest,
+			body:     "Invalid argument value",
+			wantErr:  true,
+		},
+		{
+			name:    "empty name",
+			request: CreateSectionRequest{ProjectID: "2203306141"},
+			wantErr: true,
+		},
+		{
+			name:    "empty project",
+			request: CreateSectionRequest{Name: "Groceries"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			handler := requestHandler(t, http.MethodPost, "/rest/v2/sections", tc.wantBody, tc.status, tc.body)
+			api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				handler(w, r)
+			}))
+
+			got, err := api.CreateSection(tc.request)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("CreateSection() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantBody == nil && called {
+				t.Errorf("Unexpected request for invalid section")
+			}
+			if got != tc.want {
+				t.Errorf("Section does not match.\n Got: %+v\nWant: %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateSection(t *testing.T) {
+	api := newServerClient(t, requestHandler(t, http.MethodPost, "/rest/v2/sections/7025",
+		map[string]interface{}{"name": "Shopping"},
+		http.StatusOK, `{"id":"7025","project_id":"2203306141","order":1,"name":"Shopping"}`))
+
+	got, err := api.UpdateSection("7025", "Shopping")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := Section{ID: "7025", ProjectID: "2203306141", Order: 1, Name: "Shopping"}
+	if got != want {
+		t.Errorf("Section does not match.\n Got: %+v\nWant: %+v", got, want)
+	}
+
+	if _, err := api.UpdateSection("", "Shopping"); err == nil {
+		t.Errorf("UpdateSection(\"\") did not return an error")
+	}
+}
+
+func TestDeleteSection(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus int
+	}{
+		{"deleted", http.StatusNoContent, 0},
+		{"not found", http.StatusNotFound, http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			api := newServerClient(t, requestHandler(t, http.MethodDelete, "/rest/v2/sections/7025", nil, tc.status, ""))
+
+			err := api.DeleteSection("7025")
+			if tc.wantStatus == 0 {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			apiErr, ok := err.(*APIErrorResponse)
+			if !ok || apiErr.StatusCode != tc.wantStatus {
+				t.Errorf("Err = %v, want status %d", err, tc.wantStatus)
+			}
+		})
+	}
+
+	if err := (&TodoistClient{}).DeleteSection(""); err == nil {
+		t.Errorf("DeleteSection(\"\") did not return an error")
+	}
+}
+
+func TestGroupTasksBySection(t *testing.T) {
+	sections := []Section{
+		{ID: "s2", Name: "Later", Order: 2},
+		{ID: "s1", Name: "Next", Order: 1},
+		{ID: "s3", Name: "Empty", Order: 3},
+	}
+	tasks := []Task{
+		{ID: "t1", SectionID: "s2", Order: 2},
+		{ID: "t2", SectionID: "s2", Order: 1},
+		{ID: "t3", Order: 1},
+		{ID: "t4", SectionID: "s1", Order: 1},
+		{ID: "t5", SectionID: "unknown", Order: 0},
+	}
+
+	groups := GroupTasksBySection(sections, tasks)
+
+	type group struct {
+		name    string
+		taskIDs []string
+	}
+	var got []group
+	for _, g := range groups {
+		got = append(got, group{g.Section.Name, taskIDs(g.Tasks)})
+	}
+
+	want := []group{
+		{NoSectionName, []string{"t5", "t3"}},
+		{"Next", []string{"t4"}},
+		{"Later", []string{"t2", "t1"}},
+		{"Empty", []string{}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Groups do not match.\n Got: %+v\nWant: %+v\n", got, want)
+	}
+}
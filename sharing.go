@@ -0,0 +1,230 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Live notification types.
+const (
+	NotificationShareInvitationSent     = "share_invitation_sent"
+	NotificationShareInvitationAccepted = "share_invitation_accepted"
+	NotificationShareInvitationRejected = "share_invitation_rejected"
+	NotificationUserLeftProject         = "user_left_project"
+	NotificationUserRemovedFromProject  = "user_removed_from_project"
+	NotificationItemAssigned            = "item_assigned"
+	NotificationItemCompleted           = "item_completed"
+	NotificationItemUncompleted         = "item_uncompleted"
+	NotificationNoteAdded               = "note_added"
+)
+
+// Invitation states of a share invitation.
+const (
+	InvitationInvited  = "invited"
+	InvitationAccepted = "accepted"
+	InvitationRejected = "rejected"
+	InvitationDeleted  = "deleted"
+)
+
+// A NotificationUser is the user that caused a live notification.
+type NotificationUser struct {
+	ID       string `json:"id"`
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+	ImageID  string `json:"image_id,omitempty"`
+}
+
+// A LiveNotification is a notification shown to the user, such as an
+// invitation to a shared project or a task assigned to the user. Fields
+// other than the common ones are only set for the related types.
+// See https://developer.todoist.com/sync/v9/#live-notifications
+type LiveNotification struct {
+	ID               string            `json:"id"`
+	CreatedAt        string            `json:"created_at"`
+	FromUID          string            `json:"from_uid"`
+	FromUser         *NotificationUser `json:"from_user,omitempty"`
+	NotificationKey  string            `json:"notification_key"`
+	NotificationType string            `json:"notification_type"`
+	SeqNo            int64             `json:"seq_no"`
+	IsUnread         bool              `json:"is_unread"`
+	IsDeleted        bool              `json:"is_deleted"`
+
+	ProjectID   string `json:"project_id,omitempty"`
+	ProjectName string `json:"project_name,omitempty"`
+
+	// InvitationID, InvitationSecret, and State describe a share
+	// invitation. State is one of the Invitation constants.
+	InvitationID     string `json:"invitation_id,omitempty"`
+	InvitationSecret string `json:"invitation_secret,omitempty"`
+	State            string `json:"state,omitempty"`
+
+	ItemID         string `json:"item_id,omitempty"`
+	ItemContent    string `json:"item_content,omitempty"`
+	ResponsibleUID string `json:"responsible_uid,omitempty"`
+	NoteID         string `json:"note_id,omitempty"`
+	NoteContent    string `json:"note_content,omitempty"`
+	RemovedUID     string `json:"removed_uid,omitempty"`
+}
+
+// IsPendingInvitation reports whether n is an invitation to a shared
+// project that has not been accepted, rejected, or deleted.
+func (n LiveNotification) IsPendingInvitation() bool {
+	return n.NotificationType == NotificationShareInvitationSent &&
+		n.State == InvitationInvited && n.InvitationID != ""
+}
+
+// ShareProject invites the person with email to the project for the given
+// project_id.
+// See https://developer.todoist.com/sync/v9/#share-a-project
+func (c *TodoistClient) ShareProject(ctx context.Context, project_id string, email string) error {
+	if project_id == "" {
+		return fmt.Errorf("empty project ID")
+	}
+	if email == "" {
+		return fmt.Errorf("empty email")
+	}
+
+	_, err := c.executeCommand(ctx, NewCommand("share_project",
+		map[string]string{"project_id": project_id, "email": email}))
+
+	return err
+}
+
+// RemoveCollaborator removes the person with email from the project for
+// the given project_id.
+// See https://developer.todoist.com/sync/v9/#delete-a-collaborator
+func (c *TodoistClient) RemoveCollaborator(ctx context.Context, project_id string, email string) error {
+	if project_id == "" {
+		return fmt.Errorf("empty project ID")
+	}
+	if email == "" {
+		return fmt.Errorf("empty email")
+	}
+
+	_, err := c.executeCommand(ctx, NewCommand("delete_collaborator",
+		map[string]string{"project_id": project_id, "email": email}))
+
+	return err
+}
+
+// GetLiveNotifications returns the live notifications, newest first.
+func (c *TodoistClient) GetLiveNotifications(ctx context.Context) ([]LiveNotification, error) {
+	response, err := c.Sync(ctx, "", []string{ResourceLiveNotifications})
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := make([]LiveNotification, 0, len(response.LiveNotifications))
+	for _, notification := range response.LiveNotifications {
+		if !notification.IsDeleted {
+			notifications = append(notifications, notification)
+		}
+	}
+
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].SeqNo > notifications[j].SeqNo
+	})
+
+	return notifications, nil
+}
+
+// GetPendingInvitations returns the live notifications for invitations
+// to shared projects that have not been accepted or rejected.
+func (c *TodoistClient) GetPendingInvitations(ctx context.Context) ([]LiveNotification, error) {
+	notifications, err := c.GetLiveNotifications(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var invitations []LiveNotification
+	for _, notification := range notifications {
+		if notification.IsPendingInvitation() {
+			invitations = append(invitations, notification)
+		}
+	}
+
+	return invitations, nil
+}
+
+// invitationCommand executes the command of type for the invitation.
+func (c *TodoistClient) invitationCommand(ctx context.Context, typ string, id string, secret string) error {
+	if id == "" {
+		return fmt.Errorf("empty invitation ID")
+	}
+
+	args := map[string]string{"invitation_id": id}
+	if secret != "" {
+		args["invitation_secret"] = secret
+	}
+
+	_, err := c.executeCommand(ctx, NewCommand(typ, args))
+
+	return err
+}
+
+// AcceptInvitation accepts the invitation to a shared project with id and
+// secret, as found in a pending invitation notification.
+// See https://developer.todoist.com/sync/v9/#accept-an-invitation
+func (c *TodoistClient) AcceptInvitation(ctx context.Context, id string, secret string) error {
+	if secret == "" {
+		return fmt.Errorf("empty invitation secret")
+	}
+
+	return c.invitationCommand(ctx, "accept_invitation", id, secret)
+}
+
+// RejectInvitation rejects the invitation to a shared project with id and
+// secret, as found in a pending invitation notification.
+// See https://developer.todoist.com/sync/v9/#reject-an-invitation
+func (c *TodoistClient) RejectInvitation(ctx context.Context, id string, secret string) error {
+	if secret == "" {
+		return fmt.Errorf("empty invitation secret")
+	}
+
+	return c.invitationCommand(ctx, "reject_invitation", id, secret)
+}
+
+// DeleteInvitation deletes an invitation sent by the user with id.
+// See https://developer.todoist.com/sync/v9/#delete-an-invitation
+func (c *TodoistClient) DeleteInvitation(ctx context.Context, id string) error {
+	return c.invitationCommand(ctx, "delete_invitation", id, "")
+}
+
+// MarkNotificationsRead marks the live notifications with ids as read.
+// See https://developer.todoist.com/sync/v9/#mark-a-notification-as-read
+func (c *TodoistClient) MarkNotificationsRead(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("no notification IDs")
+	}
+
+	_, err := c.executeCommand(ctx, NewCommand("live_notifications_mark_read",
+		map[string][]string{"ids": ids}))
+
+	return err
+}
+
+// MarkAllNotificationsRead marks all live notifications as read.
+// See https://developer.todoist.com/sync/v9/#mark-all-notifications-as-read
+func (c *TodoistClient) MarkAllNotificationsRead(ctx context.Context) error {
+	_, err := c.executeCommand(ctx,
+		NewCommand("live_notifications_mark_read_all", map[string]string{}))
+
+	return err
+}
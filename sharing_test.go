@@ -0,0 +1,144 @@
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetPendingInvitations(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.FormValue("resource_types"); got != `["live_notifications"]` {
+			t.Errorf("resource_types = %q", got)
+		}
+		w.Write([]byte(`{
+			"sync_token": "token",
+			"full_sync": true,
+			"live_notifications": [
+				{"id": "1", "seq_no": 1, "notification_type": "share_invitation_sent", "state": "accepted", "invitation_id": "i1", "invitation_secret": "s1"},
+				{"id": "2", "seq_no": 3, "notification_type": "share_invitation_sent", "state": "invited", "invitation_id": "i2", "invitation_secret": "s2",
+				 "project_id": "2203306141", "project_name": "Acme", "from_user": {"id": "1855589", "email": "ann@example.com", "full_name": "Ann"}},
+				{"id": "3", "seq_no": 4, "notification_type": "item_assigned", "is_unread": true, "item_id": "2995104339", "item_content": "Review"},
+				{"id": "4", "seq_no": 2, "notification_type": "share_invitation_sent", "state": "invited", "invitation_id": "i4", "invitation_secret": "s4", "is_deleted": true}
+			]
+		}`))
+	}))
+	ctx := context.Background()
+
+	notifications, err := api.GetLiveNotifications(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, notification := range notifications {
+		ids = append(ids, notification.ID)
+	}
+	if want := []string{"3", "2", "1"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Notification IDs = %v, want %v", ids, want)
+	}
+	if n := notifications[0]; !n.IsUnread || n.ItemContent != "Review" {
+		t.Errorf("Unexpected notification: %+v", n)
+	}
+
+	invitations, err := api.GetPendingInvitations(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(invitations) != 1 {
+		t.Fatalf("Got %d invitations, want 1", len(invitations))
+	}
+	if i := invitations[0]; i.InvitationID != "i2" || i.InvitationSecret != "s2" ||
+		i.ProjectName != "Acme" || i.FromUser == nil || i.FromUser.Email != "ann@example.com" {
+		t.Errorf("Unexpected invitation: %+v", i)
+	}
+}
+
+func TestSharingCommands(t *testing.T) {
+	var commands []Command
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var cmds []Command
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &cmds); err != nil {
+			t.Errorf("Cannot decode commands: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		commands = append(commands, cmds...)
+
+		status := make(map[string]string)
+		for _, cmd := range cmds {
+			status[cmd.UUID] = "ok"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"sync_status": status})
+	}))
+	ctx := context.Background()
+
+	calls := []struct {
+		name string
+		call func() error
+	}{
+		{"ShareProject", func() error { return api.ShareProject(ctx, "2203306141", "bob@example.com") }},
+		{"RemoveCollaborator", func() error { return api.RemoveCollaborator(ctx, "2203306141", "bob@example.com") }},
+		{"AcceptInvitation", func() error { return api.AcceptInvitation(ctx, "i2", "s2") }},
+		{"RejectInvitation", func() error { return api.RejectInvitation(ctx, "i3", "s3") }},
+		{"DeleteInvitation", func() error { return api.DeleteInvitation(ctx, "i4") }},
+		{"MarkNotificationsRead", func() error { return api.MarkNotificationsRead(ctx, "1", "3") }},
+		{"MarkAllNotificationsRead", func() error { return api.MarkAllNotificationsRead(ctx) }},
+	}
+	for _, c := range calls {
+		if err := c.call(); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+	}
+
+	tests := []struct {
+		Type string
+		Args string
+	}{
+		{"share_project", `{"email":"bob@example.com","project_id":"2203306141"}`},
+		{"delete_collaborator", `{"email":"bob@example.com","project_id":"2203306141"}`},
+		{"accept_invitation", `{"invitation_id":"i2","invitation_secret":"s2"}`},
+		{"reject_invitation", `{"invitation_id":"i3","invitation_secret":"s3"}`},
+		{"delete_invitation", `{"invitation_id":"i4"}`},
+		{"live_notifications_mark_read", `{"ids":["1","3"]}`},
+		{"live_notifications_mark_read_all", `{}`},
+	}
+	if len(commands) != len(tests) {
+		t.Fatalf("Got %d commands, want %d", len(commands), len(tests))
+	}
+	for n, tc := range tests {
+		args, _ := json.Marshal(commands[n].Args)
+		if commands[n].Type != tc.Type || string(args) != tc.Args {
+			t.Errorf("Command %d = %s %s, want %s %s",
+				n, commands[n].Type, args, tc.Type, tc.Args)
+		}
+	}
+}
+
+func TestSharingValidation(t *testing.T) {
+	api := &TodoistClient{}
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"share without project", func() error { return api.ShareProject(ctx, "", "bob@example.com") }},
+		{"share without email", func() error { return api.ShareProject(ctx, "2203306141", "") }},
+		{"remove without email", func() error { return api.RemoveCollaborator(ctx, "2203306141", "") }},
+		{"accept without secret", func() error { return api.AcceptInvitation(ctx, "i2", "") }},
+		{"reject without ID", func() error { return api.RejectInvitation(ctx, "", "s2") }},
+		{"delete without ID", func() error { return api.DeleteInvitation(ctx, "") }},
+		{"mark read without IDs", func() error { return api.MarkNotificationsRead(ctx) }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.call(); err == nil {
+				t.Errorf("Expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,427 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// syncBase is the base URL of the Todoist Sync API.
+// See https://developer.todoist.com/sync/v9/
+const syncBase = "https://api.todoist.com/sync/v9"
+
+// A Command is a Sync API command that adds, updates, or removes an object.
+// See https://developer.todoist.com/sync/v9/#write-resources
+type Command struct {
+	Type   string      `json:"type"`
+	UUID   string      `json:"uuid"`
+	TempID string      `json:"temp_id,omitempty"`
+	Args   interface{} `json:"args"`
+}
+
+// NewCommand returns a Command of type cmdType with args and a new UUID.
+func NewCommand(cmdType string, args interface{}) Command {
+	return Command{Type: cmdType, UUID: newUUID(), Args: args}
+}
+
+// A CommandError is returned for a Sync API command that failed.
+type CommandError struct {
+	UUID      string `json:"-"`
+	ErrorCode int    `json:"error_code"`
+	Err       string `json:"error"`
+}
+
+// Error returns a string representation of the error.
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("command %s failed: %s (error code %d)",
+		e.UUID, e.Err, e.ErrorCode)
+}
+
+// A CommandResponse is the response to Sync API commands.
+type CommandResponse struct {
+	SyncToken     string                     `json:"sync_token"`
+	SyncStatus    map[string]json.RawMessage `json:"sync_status"`
+	TempIDMapping map[string]string          `json:"temp_id_mapping"`
+}
+
+// Err returns a CommandError if the command with uuid failed, or nil if
+// it succeeded. An error is also returned if there is no status for uuid.
+func (r *CommandResponse) Err(uuid string) error {
+	status, ok := r.SyncStatus[uuid]
+	if !ok {
+		return fmt.Errorf("no status for command %s", uuid)
+	}
+
+	var s string
+	if json.Unmarshal(status, &s) == nil && s == "ok" {
+		return nil
+	}
+
+	cmdErr := &CommandError{UUID: uuid}
+	err := json.Unmarshal(status, cmdErr)
+	if err != nil || cmdErr.Err == "" {
+		cmdErr.Err = string(status)
+	}
+
+	return cmdErr
+}
+
+// SyncPost executes the Todoist Sync API call for endpoint with the form
+// encoded data, returning the response body.
+//
+// See https://developer.todoist.com/sync/v9/#overview
+func (c *TodoistClient) SyncPost(ctx context.Context, endpoint string, form url.Values) (body []byte, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		syncBase+endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return body, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return c.do(req)
+}
+
+// ExecuteCommands sends cmds to the Sync API in a single request,
+// returning the response. Use CommandResponse.Err to check each command.
+func (c *TodoistClient) ExecuteCommands(ctx context.Context, cmds []Command) (*CommandResponse, error) {
+	if len(cmds) == 0 {
+		return nil, fmt.Errorf("no commands")
+	}
+
+	var buffer bytes.Buffer
+	err := json.NewEncoder(&buffer).Encode(cmds)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("commands", buffer.String())
+
+	body, err := c.SyncPost(ctx, "/sync", form)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CommandResponse{}
+	err = json.Unmarshal(body, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// executeCommand sends a single command to the Sync API, returning an
+// error if the request or the command failed.
+func (c *TodoistClient) executeCommand(ctx context.Context, cmd Command) (*CommandResponse, error) {
+	response, err := c.ExecuteCommands(ctx, []Command{cmd})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, response.Err(cmd.UUID)
+}
+
+// Resource types that can be requested by Sync.
+// See https://developer.todoist.com/sync/v9/#read-resources
+const (
+	ResourceAll          = "all"
+	ResourceItems        = "items"
+	ResourceProjects     = "projects"
+	ResourceSections     = "sections"
+	ResourceLabels       = "labels"
+	ResourceNotes        = "notes"
+	ResourceProjectNotes = "project_notes"
+	ResourceReminders    = "reminders"
+	ResourceFilters      = "filters"
+	ResourceUser         = "user"
+	ResourceUserSettings = "user_settings"
+
+	ResourceLiveNotifications = "live_notifications"
+)
+
+// fullSyncToken is the sync token that requests a full sync.
+const fullSyncToken = "*"
+
+// A SyncResponse is the response to a Sync API read. For an incremental
+// sync, only the objects changed since the previous sync are included,
+// with deleted objects marked by IsDeleted.
+type SyncResponse struct {
+	SyncToken string `json:"sync_token"`
+
+	// FullSync is true if the response contains all objects rather than
+	// the changes since the previous sync.
+	FullSync bool `json:"full_sync"`
+
+	// ForcedFullSync is true if an incremental sync was requested but a
+	// full sync was returned, e.g., because the sync token expired.
+	// Any local copy of the data must be replaced rather than updated.
+	ForcedFullSync bool `json:"-"`
+
+	Items        []SyncItem    `json:"items"`
+	Projects     []SyncProject `json:"projects"`
+	Sections     []SyncSection `json:"sections"`
+	Labels       []SyncLabel   `json:"labels"`
+	Notes        []SyncNote    `json:"notes"`
+	ProjectNotes []SyncNote    `json:"project_notes"`
+	Reminders    []Reminder    `json:"reminders"`
+	Filters      []Filter      `json:"filters"`
+	User         *User         `json:"user"`
+	UserSettings *UserSettings `json:"user_settings"`
+
+	LiveNotifications           []LiveNotification `json:"live_notifications"`
+	LiveNotificationsLastReadID string             `json:"live_notifications_last_read_id"`
+}
+
+// Sync reads resourceTypes changed since syncToken, or all of them if
+// syncToken is empty. Use ResourceAll to read all resource types.
+//
+// See https://developer.todoist.com/sync/v9/#read-resources
+func (c *TodoistClient) Sync(ctx context.Context, syncToken string, resourceTypes []string) (*SyncResponse, error) {
+	if len(resourceTypes) == 0 {
+		return nil, fmt.Errorf("no resource types")
+	}
+
+	if syncToken == "" {
+		syncToken = fullSyncToken
+	}
+
+	data, err := json.Marshal(resourceTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("sync_token", syncToken)
+	form.Set("resource_types", string(data))
+
+	body, err := c.SyncPost(ctx, "/sync", form)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SyncResponse{}
+	err = json.Unmarshal(body, response)
+	if err != nil {
+		return nil, err
+	}
+
+	response.ForcedFullSync = syncToken != fullSyncToken && response.FullSync
+
+	return response, nil
+}
+
+// A SyncTokenStore persists sync tokens between syncs. Tokens are stored
+// by a key for the requested resource types.
+type SyncTokenStore interface {
+	// LoadSyncToken returns the sync token for key, or an empty string
+	// if there is none.
+	LoadSyncToken(key string) (string, error)
+
+	// SaveSyncToken saves the sync token for key.
+	SaveSyncToken(key string, token string) error
+}
+
+// A MemorySyncTokenStore is a SyncTokenStore that keeps tokens in memory.
+type MemorySyncTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// LoadSyncToken returns the sync token for key.
+func (m *MemorySyncTokenStore) LoadSyncToken(key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.tokens[key], nil
+}
+
+// SaveSyncToken saves the sync token for key.
+func (m *MemorySyncTokenStore) SaveSyncToken(key string, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.tokens == nil {
+		m.tokens = make(map[string]string)
+	}
+	m.tokens[key] = token
+
+	return nil
+}
+
+// A FileSyncTokenStore is a SyncTokenStore that keeps tokens in a JSON
+// encoded file, which is created when the first token is saved.
+type FileSyncTokenStore struct {
+	FileName string
+
+	mu sync.Mutex
+}
+
+// read returns the tokens from the file.
+func (f *FileSyncTokenStore) read() (map[string]string, error) {
+	tokens := make(map[string]string)
+
+	data, err := ioutil.ReadFile(f.FileName)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &tokens)
+
+	return tokens, err
+}
+
+// LoadSyncToken returns the sync token for key.
+func (f *FileSyncTokenStore) LoadSyncToken(key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tokens, err := f.read()
+	if err != nil {
+		return "", err
+	}
+
+	return tokens[key], nil
+}
+
+// SaveSyncToken saves the sync token for key.
+func (f *FileSyncTokenStore) SaveSyncToken(key string, token string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tokens, err := f.read()
+	if err != nil {
+		return err
+	}
+	tokens[key] = token
+
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// replace the file only once the tokens are fully written
+	return writeFileAtomic(data, f.FileName)
+}
+
+// A Syncer performs incremental syncs, reusing the sync token saved by the
+// previous sync of the same resource types.
+type Syncer struct {
+	client *TodoistClient
+	store  SyncTokenStore
+}
+
+// NewSyncer returns a Syncer using client that saves sync tokens in
+// store. If store is nil, tokens are kept in memory.
+func NewSyncer(client *TodoistClient, store SyncTokenStore) *Syncer {
+	if store == nil {
+		store = &MemorySyncTokenStore{}
+	}
+
+	return &Syncer{client: client, store: store}
+}
+
+// syncTokenKey returns the key for the sync token of resourceTypes.
+func syncTokenKey(resourceTypes []string) string {
+	sorted := append([]string(nil), resourceTypes...)
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, ",")
+}
+
+// Sync reads resourceTypes changed since the previous sync, or all of them
+// if there was no previous sync, and saves the new sync token.
+//
+// If the saved sync token is rejected by the API, a full sync is done
+// instead. Check SyncResponse.ForcedFullSync to detect a full sync that
+// was not requested.
+func (s *Syncer) Sync(ctx context.Context, resourceTypes ...string) (*SyncResponse, error) {
+	key := syncTokenKey(resourceTypes)
+
+	token, err := s.store.LoadSyncToken(key)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.client.Sync(ctx, token, resourceTypes)
+
+	// an invalid or expired token is rejected as a bad request
+	var apiErr *APIErrorResponse
+	if token != "" && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+		response, err = s.client.Sync(ctx, "", resourceTypes)
+		if err == nil {
+			response.ForcedFullSync = true
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.store.SaveSyncToken(key, response.SyncToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// FullSync reads all of resourceTypes, ignoring any previous sync, and
+// saves the new sync token.
+func (s *Syncer) FullSync(ctx context.Context, resourceTypes ...string) (*SyncResponse, error) {
+	response, err := s.client.Sync(ctx, "", resourceTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.store.SaveSyncToken(syncTokenKey(resourceTypes), response.SyncToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	b := make([]byte, 16)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,142 @@
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSyncDecode(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sync/v9/sync" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		if got := r.FormValue("sync_token"); got != "*" {
+			t.Errorf("sync_token = %q, want %q", got, "*")
+		}
+		if got := r.FormValue("resource_types"); got != `["all"]` {
+			t.Errorf("resource_types = %q, want %q", got, `["all"]`)
+		}
+		http.ServeFile(w, r, "testdata/sync_full.json")
+	}))
+
+	response, err := api.Sync(context.Background(), "", []string{ResourceAll})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !response.FullSync || response.ForcedFullSync || response.SyncToken != "token-1" {
+		t.Errorf("Unexpected sync state: %+v", response)
+	}
+
+	counts := map[string]int{
+		"items":     len(response.Items),
+		"projects":  len(response.Projects),
+		"sections":  len(response.Sections),
+		"labels":    len(response.Labels),
+		"notes":     len(response.Notes),
+		"reminders": len(response.Reminders),
+		"filters":   len(response.Filters),
+	}
+	for name, count := range counts {
+		if count != 1 {
+			t.Errorf("Got %d %s, want 1", count, name)
+		}
+	}
+
+	task := response.Items[0].Task()
+	if task.ID != "2995104339" || task.SectionID != "7025" || task.Due.Date != "2016-09-01" {
+		t.Errorf("Unexpected task: %+v", task)
+	}
+	if got := response.Sections[0].Section(); got != (Section{ID: "7025", ProjectID: "2203306141", Order: 1, Name: "Groceries"}) {
+		t.Errorf("Unexpected section: %+v", got)
+	}
+	if got := response.Notes[0].Comment(); got.TaskID != "2995104339" || got.Content != "Note" {
+		t.Errorf("Unexpected comment: %+v", got)
+	}
+	if got := response.Reminders[0]; got.Type != ReminderRelative || got.MinuteOffset != 30 {
+		t.Errorf("Unexpected reminder: %+v", got)
+	}
+	if got := response.Filters[0]; got.Query != "priority 1" {
+		t.Errorf("Unexpected filter: %+v", got)
+	}
+	if response.User == nil || response.User.TzInfo.Timezone != "Europe/Rome" {
+		t.Errorf("Unexpected user: %+v", response.User)
+	}
+}
+
+func TestSyncer(t *testing.T) {
+	var tokens []string
+
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.FormValue("sync_token")
+		tokens = append(tokens, token)
+
+		switch token {
+		case "*":
+			json.NewEncoder(w).Encode(map[string]interface{}{"sync_token": "token-1", "full_sync": true})
+		case "token-1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"sync_token": "token-2",
+				"full_sync":  false,
+				"items":      []map[string]interface{}{{"id": "1", "is_deleted": true}},
+			})
+		case "token-2":
+			// the server no longer accepts the token
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, "Invalid sync token")
+		case "token-3":
+			// the server decides a full sync is needed
+			json.NewEncoder(w).Encode(map[string]interface{}{"sync_token": "token-4", "full_sync": true})
+		}
+	}))
+
+	store := &FileSyncTokenStore{FileName: filepath.Join(t.TempDir(), "tokens.json")}
+	syncer := NewSyncer(api, store)
+	ctx := context.Background()
+
+	response, err := syncer.Sync(ctx, ResourceItems, ResourceProjects)
+	if err != nil || !response.FullSync || response.ForcedFullSync {
+		t.Fatalf("First sync = %+v, %v", response, err)
+	}
+
+	// the key does not depend on the order of the resource types
+	response, err = syncer.Sync(ctx, ResourceProjects, ResourceItems)
+	if err != nil || response.FullSync || len(response.Items) != 1 || !response.Items[0].IsDeleted {
+		t.Fatalf("Incremental sync = %+v, %v", response, err)
+	}
+
+	response, err = syncer.Sync(ctx, ResourceItems, ResourceProjects)
+	if err != nil || !response.ForcedFullSync {
+		t.Fatalf("Sync with rejected token = %+v, %v", response, err)
+	}
+
+	store.SaveSyncToken(syncTokenKey([]string{ResourceItems, ResourceProjects}), "token-3")
+	response, err = syncer.Sync(ctx, ResourceItems, ResourceProjects)
+	if err != nil || !response.ForcedFullSync {
+		t.Fatalf("Sync with forced full sync = %+v, %v", response, err)
+	}
+
+	wantTokens := []string{"*", "token-1", "token-2", "*", "token-3"}
+	if !reflect.DeepEqual(tokens, wantTokens) {
+		t.Errorf("Tokens sent.\n Got: %v\nWant: %v", tokens, wantTokens)
+	}
+
+	// a new store reads the token saved by the previous store
+	reloaded := &FileSyncTokenStore{FileName: store.FileName}
+	token, err := reloaded.LoadSyncToken(syncTokenKey([]string{ResourceProjects, ResourceItems}))
+	if err != nil || token != "token-4" {
+		t.Errorf("LoadSyncToken() = %q, %v, want token-4", token, err)
+	}
+
+	// tokens are written to a temporary file that replaces the store
+	files, err := ioutil.ReadDir(filepath.Dir(store.FileName))
+	if err != nil || len(files) != 1 || files[0].Name() != "tokens.json" {
+		t.Errorf("Store directory has %d files, want only tokens.json: %v", len(files), err)
+	}
+}
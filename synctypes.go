@@ -0,0 +1,194 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import "time"
+
+// A SyncItem is a task as represented by the Sync API.
+// See https://developer.todoist.com/sync/v9/#items
+type SyncItem struct {
+	ID             string        `json:"id"`
+	UserID         string        `json:"user_id"`
+	ProjectID      string        `json:"project_id"`
+	SectionID      string        `json:"section_id"`
+	ParentID       string        `json:"parent_id"`
+	Content        string        `json:"content"`
+	Description    string        `json:"description"`
+	Checked        bool          `json:"checked"`
+	IsDeleted      bool          `json:"is_deleted"`
+	Labels         []string      `json:"labels"`
+	ChildOrder     int           `json:"child_order"`
+	DayOrder       int           `json:"day_order"`
+	Collapsed      bool          `json:"collapsed"`
+	Priority       int           `json:"priority"`
+	Due            *TaskDue      `json:"due"`
+	AddedAt        time.Time     `json:"added_at"`
+	AddedByUID     string        `json:"added_by_uid"`
+	ResponsibleUID string        `json:"responsible_uid"`
+	AssignedByUID  string        `json:"assigned_by_uid"`
+	CompletedAt    *time.Time    `json:"completed_at"`
+	Duration       *TaskDuration `json:"duration"`
+	Deadline       *TaskDeadline `json:"deadline"`
+}
+
+// Task returns the item as a Task. The Task shares no memory with the item.
+func (i SyncItem) Task() Task {
+	task := Task{
+		ID:          i.ID,
+		ProjectID:   i.ProjectID,
+		SectionID:   i.SectionID,
+		Content:     i.Content,
+		Description: i.Description,
+		IsCompleted: i.Checked,
+		ParentID:    i.ParentID,
+		Order:       i.ChildOrder,
+		Priority:    i.Priority,
+		CreatedAt:   i.AddedAt,
+		CreatorID:   i.AddedByUID,
+		AssigneeID:  i.ResponsibleUID,
+		AssignerID:  i.AssignedByUID,
+	}
+
+	if i.Labels != nil {
+		task.Labels = append([]string{}, i.Labels...)
+	}
+	if i.Due != nil {
+		due := *i.Due
+		task.Due = &due
+	}
+	if i.Duration != nil {
+		duration := *i.Duration
+		task.Duration = &duration
+	}
+	if i.Deadline != nil {
+		deadline := *i.Deadline
+		task.Deadline = &deadline
+	}
+
+	return task
+}
+
+// A SyncProject is a project as represented by the Sync API.
+// See https://developer.todoist.com/sync/v9/#projects
+type SyncProject struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	Color        string  `json:"color"`
+	ParentID     *string `json:"parent_id"`
+	ChildOrder   int     `json:"child_order"`
+	Collapsed    bool    `json:"collapsed"`
+	Shared       bool    `json:"shared"`
+	IsFavorite   bool    `json:"is_favorite"`
+	IsArchived   bool    `json:"is_archived"`
+	IsDeleted    bool    `json:"is_deleted"`
+	InboxProject bool    `json:"inbox_project"`
+	TeamInbox    bool    `json:"team_inbox"`
+	ViewStyle    string  `json:"view_style"`
+}
+
+// Project returns the SyncProject as a Project. The Project shares no
+// memory with the SyncProject.
+func (p SyncProject) Project() Project {
+	project := Project{
+		ID:             p.ID,
+		Name:           p.Name,
+		Color:          p.Color,
+		Order:          p.ChildOrder,
+		IsShared:       p.Shared,
+		IsFavorite:     p.IsFavorite,
+		IsInboxProject: p.InboxProject,
+		IsTeamInbox:    p.TeamInbox,
+		ViewStyle:      p.ViewStyle,
+		IsArchived:     p.IsArchived,
+	}
+
+	if p.ParentID != nil {
+		parentID := *p.ParentID
+		project.ParentID = &parentID
+	}
+
+	return project
+}
+
+// A SyncSection is a section as represented by the Sync API.
+// See https://developer.todoist.com/sync/v9/#sections
+type SyncSection struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	ProjectID    string `json:"project_id"`
+	SectionOrder int    `json:"section_order"`
+	Collapsed    bool   `json:"collapsed"`
+	IsArchived   bool   `json:"is_archived"`
+	IsDeleted    bool   `json:"is_deleted"`
+}
+
+// Section returns the SyncSection as a Section.
+func (s SyncSection) Section() Section {
+	return Section{
+		ID:        s.ID,
+		ProjectID: s.ProjectID,
+		Order:     s.SectionOrder,
+		Name:      s.Name,
+	}
+}
+
+// A SyncLabel is a personal label as represented by the Sync API.
+// See https://developer.todoist.com/sync/v9/#labels
+type SyncLabel struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Color      string `json:"color"`
+	ItemOrder  int    `json:"item_order"`
+	IsFavorite bool   `json:"is_favorite"`
+	IsDeleted  bool   `json:"is_deleted"`
+}
+
+// PersonalLabel returns the SyncLabel as a PersonalLabel.
+func (l SyncLabel) PersonalLabel() PersonalLabel {
+	return PersonalLabel{
+		ID:         l.ID,
+		Name:       l.Name,
+		Color:      l.Color,
+		Order:      l.ItemOrder,
+		IsFavorite: l.IsFavorite,
+	}
+}
+
+// A SyncNote is a task or project comment as represented by the Sync API.
+// See https://developer.todoist.com/sync/v9/#item-notes
+type SyncNote struct {
+	ID             string      `json:"id"`
+	ItemID         string      `json:"item_id,omitempty"`
+	ProjectID      string      `json:"project_id,omitempty"`
+	PostedUID      string      `json:"posted_uid"`
+	Content        string      `json:"content"`
+	FileAttachment *Attachment `json:"file_attachment"`
+	PostedAt       time.Time   `json:"posted_at"`
+	IsDeleted      bool        `json:"is_deleted"`
+}
+
+// Comment returns the SyncNote as a Comment.
+func (n SyncNote) Comment() Comment {
+	return Comment{
+		ID:         n.ID,
+		TaskID:     n.ItemID,
+		ProjectID:  n.ProjectID,
+		PostedAt:   n.PostedAt,
+		Content:    n.Content,
+		Attachment: n.FileAttachment,
+	}
+}
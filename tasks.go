@@ -18,6 +18,7 @@ package tdapi
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
@@ -25,35 +26,63 @@ import (
 )
 
 // See https://developer.todoist.com/rest/v2/?shell#tasks
-type TaskDue struct {
-	String      string     `json:"string"`
-	Date        string     `json:"date"`
-	IsRecurring bool       `json:"is_recurring"`
-	Datetime    *time.Time `json:"datetime,omitempty"`
-	Timezone    string     `json:"timezone,omitempty"`
+type Task struct {
+	ID           string        `json:"id"`
+	ProjectID    string        `json:"project_id"`
+	SectionID    string        `json:"section_id,omitempty"`
+	Content      string        `json:"content"`
+	Description  string        `json:"description,omitempty"`
+	IsCompleted  bool          `json:"is_completed"`
+	Labels       []string      `json:"labels,omitempty"`
+	ParentID     string        `json:"parent_id,omitempty"`
+	Order        int           `json:"order"`
+	Priority     int           `json:"priority"`
+	Due          *TaskDue      `json:"due,omitempty"`
+	URL          string        `json:"url,omitempty"`
+	CommentCount int           `json:"comment_count,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	CreatorID    string        `json:"creator_id"`
+	AssigneeID   string        `json:"assignee_id,omitempty"`
+	AssignerID   string        `json:"assigner_id,omitempty"`
+	Duration     *TaskDuration `json:"duration,omitempty"`
+	Deadline     *TaskDeadline `json:"deadline,omitempty"`
 }
 
+// DurationUnit is the unit of a TaskDuration.
+type DurationUnit string
+
+const (
+	DurationMinute DurationUnit = "minute"
+	DurationDay    DurationUnit = "day"
+)
+
+// A TaskDuration is the amount of time a task will take.
 // See https://developer.todoist.com/rest/v2/?shell#tasks
-type Task struct {
-	ID           string    `json:"id"`
-	ProjectID    string    `json:"project_id"`
-	SectionID    string    `json:"section_id,omitempty"`
-	Content      string    `json:"content"`
-	Description  string    `json:"description,omitempty"`
-	IsCompleted  bool      `json:"is_completed"`
-	Labels       []string  `json:"labels,omitempty"`
-	ParentID     string    `json:"parent,omitempty"`
-	Order        int       `json:"order"`
-	Priority     int       `json:"priority"`
-	Due          *TaskDue  `json:"due,omitempty"`
-	URL          string    `json:"url,omitempty"`
-	CommentCount int       `json:"comment_count,omitempty"`
-	CreatedAt    time.Time `json:"created_at"`
-	CreatorID    string    `json:"creator_id"`
-	AssigneeID   string    `json:"assignee_id,omitempty"`
-	AssignerID   string    `json:"assigner_id,omitempty"`
-	Duration     int       `json:"duration"`
-	DurationUnit string    `json:"duration_unit"`
+type TaskDuration struct {
+	Amount int          `json:"amount"`
+	Unit   DurationUnit `json:"unit"`
+}
+
+// Duration returns the TaskDuration as a time.Duration, where a day is
+// 24 hours. An unknown unit returns zero.
+func (d TaskDuration) Duration() time.Duration {
+	switch d.Unit {
+	case DurationMinute:
+		return time.Duration(d.Amount) * time.Minute
+	case DurationDay:
+		return time.Duration(d.Amount) * 24 * time.Hour
+	}
+	return 0
+}
+
+// TaskDurationFrom returns a TaskDuration for dur, using days if dur is a
+// whole number of days and minutes otherwise. Partial minutes are dropped.
+func TaskDurationFrom(dur time.Duration) TaskDuration {
+	day := 24 * time.Hour
+	if dur >= day && dur%day == 0 {
+		return TaskDuration{Amount: int(dur / day), Unit: DurationDay}
+	}
+	return TaskDuration{Amount: int(dur / time.Minute), Unit: DurationMinute}
 }
 
 // See https://developer.todoist.com/rest/v2/?shell#get-active-tasks
@@ -85,7 +114,9 @@ func (c *TodoistClient) GetActiveTasks(p *TaskParameters) (response []Task, err
 			query.Set("project_id", p.ProjectID)
 		}
 
-		// TODO: section_id
+		if p.SectionID != "" {
+			query.Set("section_id", p.SectionID)
+		}
 
 		if len(p.Label) > 0 {
 			query.Set("label", p.Label)
@@ -126,6 +157,84 @@ func (c *TodoistClient) GetActiveTask(id int) (response Task, err error) {
 	return response, err
 }
 
+// A TaskRequest contains the fields to create or update a task.
+// Empty fields are not sent, so they are not changed by an update.
+//
+// See https://developer.todoist.com/rest/v2/?shell#create-a-new-task
+type TaskRequest struct {
+	Content      string        `json:"content,omitempty"`
+	Description  string        `json:"description,omitempty"`
+	ProjectID    string        `json:"project_id,omitempty"`
+	SectionID    string        `json:"section_id,omitempty"`
+	ParentID     string        `json:"parent_id,omitempty"`
+	Order        int           `json:"order,omitempty"`
+	Labels       []string      `json:"labels,omitempty"`
+	Priority     int           `json:"priority,omitempty"`
+	DueString    string        `json:"due_string,omitempty"`
+	DueDate      string        `json:"due_date,omitempty"`
+	DueDatetime  string        `json:"due_datetime,omitempty"`
+	DueLang      string        `json:"due_lang,omitempty"`
+	AssigneeID   string        `json:"assignee_id,omitempty"`
+	Duration     *TaskDuration `json:"-"`
+	DeadlineDate string        `json:"deadline_date,omitempty"`
+	DeadlineLang string        `json:"deadline_lang,omitempty"`
+}
+
+// MarshalJSON encodes the request, sending Duration as the separate
+// duration and duration_unit fields expected by the API.
+func (r TaskRequest) MarshalJSON() ([]byte, error) {
+	type request TaskRequest
+
+	v := struct {
+		request
+		Duration     int          `json:"duration,omitempty"`
+		DurationUnit DurationUnit `json:"duration_unit,omitempty"`
+	}{request: request(r)}
+
+	if r.Duration != nil {
+		v.Duration = r.Duration.Amount
+		v.DurationUnit = r.Duration.Unit
+	}
+
+	return json.Marshal(v)
+}
+
+// CreateTask creates a new task, returning the created task.
+func (c *TodoistClient) CreateTask(r TaskRequest) (response Task, err error) {
+	var body []byte
+
+	if r.Content == "" {
+		return response, fmt.Errorf("empty task content")
+	}
+
+	body, err = c.postJSON("/tasks", r)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, &response)
+
+	return response, err
+}
+
+// UpdateTask updates the task with id, returning the updated task.
+func (c *TodoistClient) UpdateTask(id string, r TaskRequest) (response Task, err error) {
+	var body []byte
+
+	if id == "" {
+		return response, fmt.Errorf("empty task ID")
+	}
+
+	body, err = c.postJSON("/tasks/"+id, r)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, &response)
+
+	return response, err
+}
+
 // GroupTasksByProjectID groups Tasks by their Project ID.
 func GroupTasksByProjectID(tasks []Task) map[string][]Task {
 	groups := make(map[string][]Task)
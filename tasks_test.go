@@ -0,0 +1,83 @@
+package tdapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTaskDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration TaskDuration
+		want     time.Duration
+	}{
+		{"minutes", TaskDuration{Amount: 15, Unit: DurationMinute}, 15 * time.Minute},
+		{"days", TaskDuration{Amount: 2, Unit: DurationDay}, 48 * time.Hour},
+		{"unknown unit", TaskDuration{Amount: 2, Unit: "week"}, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.duration.Duration(); got != tc.want {
+				t.Errorf("Duration() = %v, want %v", got, tc.want)
+			}
+			if got := TaskDurationFrom(tc.want); tc.want != 0 && got != tc.duration {
+				t.Errorf("TaskDurationFrom(%v) = %+v, want %+v", tc.want, got, tc.duration)
+			}
+		})
+	}
+}
+
+func TestCreateTaskDuration(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/v2/tasks" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+
+		var got map[string]interface{}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("Cannot decode request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		want := map[string]interface{}{
+			"content":       "Review",
+			"duration":      float64(90),
+			"duration_unit": "minute",
+			"deadline_date": "2023-03-20",
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Request does not match.\n Got: %v\nWant: %v", got, want)
+		}
+
+		io.WriteString(w, `{"id":"1","content":"Review",
+			"duration":{"amount":90,"unit":"minute"},
+			"deadline":{"date":"2023-03-20","lang":"en"}}`)
+	}))
+
+	duration := TaskDurationFrom(90 * time.Minute)
+	task, err := api.CreateTask(TaskRequest{
+		Content:      "Review",
+		Duration:     &duration,
+		DeadlineDate: "2023-03-20",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if task.Duration == nil || *task.Duration != duration {
+		t.Errorf("Duration = %+v, want %+v", task.Duration, duration)
+	}
+
+	wantDeadline := &TaskDeadline{Date: "2023-03-20", Lang: "en"}
+	if !reflect.DeepEqual(task.Deadline, wantDeadline) {
+		t.Errorf("Deadline = %+v, want %+v", task.Deadline, wantDeadline)
+	}
+}
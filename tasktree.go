@@ -0,0 +1,268 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SkipChildren is used as a return value from a walk function to indicate
+// that the children of the current node are to be skipped. It is not
+// returned as an error by any walk.
+var SkipChildren = errors.New("skip children")
+
+// TaskWalkFunc is the type of the function called by TaskTree.Walk for
+// each task. depth is zero for top level tasks.
+//
+// If the function returns SkipChildren, the children of the task are not
+// visited. Any other non-nil error stops the walk and is returned by Walk.
+type TaskWalkFunc func(task Task, depth int) error
+
+// A TaskTreeError reports parent cycles in a TaskTree.
+type TaskTreeError struct {
+	// Cycles are the tasks in each cycle of parent IDs.
+	Cycles [][]string
+}
+
+// Error returns a string representation of the error.
+func (e *TaskTreeError) Error() string {
+	var msgs []string
+
+	for _, cycle := range e.Cycles {
+		msgs = append(msgs, fmt.Sprintf("parent cycle: %s",
+			strings.Join(cycle, " -> ")))
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// A TaskTree organizes a flat list of tasks into a hierarchy using ParentID.
+//
+// Tasks whose parent is missing from the list, or whose parent is
+// completed, are orphans. Orphans are treated as top level tasks so they
+// are still visited by Walk, and are also reported by Orphans.
+//
+// A parent cycle is broken by treating the task with the lowest ID in the
+// cycle as a top level task. Use Validate to report parent cycles.
+type TaskTree struct {
+	taskByID    map[string]Task
+	parentIDs   map[string]string
+	childIDs    map[string][]string
+	orphanIDs   []string
+	isOrphan    map[string]bool
+	rootTaskIDs []string
+	treeErr     TaskTreeError
+}
+
+// NewTaskTree returns a TaskTree built from tasks.
+// Children are sorted by Order, then by ID for tasks with the same Order.
+func NewTaskTree(tasks []Task) *TaskTree {
+	tree := &TaskTree{
+		taskByID:  make(map[string]Task, len(tasks)),
+		parentIDs: make(map[string]string),
+		childIDs:  make(map[string][]string),
+		isOrphan:  make(map[string]bool),
+	}
+
+	for _, task := range tasks {
+		tree.taskByID[task.ID] = task
+	}
+
+	for _, task := range tasks {
+		if task.ParentID == "" {
+			continue
+		}
+
+		parent, ok := tree.taskByID[task.ParentID]
+		if !ok || parent.IsCompleted {
+			if !tree.isOrphan[task.ID] {
+				tree.orphanIDs = append(tree.orphanIDs, task.ID)
+				tree.isOrphan[task.ID] = true
+			}
+			continue
+		}
+
+		tree.parentIDs[task.ID] = task.ParentID
+	}
+
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	tree.treeErr.Cycles = breakParentCycles(ids, tree.parentIDs)
+
+	seen := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		if seen[task.ID] {
+			continue
+		}
+		seen[task.ID] = true
+
+		parentID, ok := tree.parentIDs[task.ID]
+		if !ok {
+			tree.rootTaskIDs = append(tree.rootTaskIDs, task.ID)
+			continue
+		}
+
+		tree.childIDs[parentID] = append(tree.childIDs[parentID], task.ID)
+	}
+
+	sortByOrder(tree.rootTaskIDs, tree.taskOrder)
+	sortByOrder(tree.orphanIDs, tree.taskOrder)
+	for _, ids := range tree.childIDs {
+		sortByOrder(ids, tree.taskOrder)
+	}
+
+	return tree
+}
+
+// Validate returns a *TaskTreeError if any task is part of a parent cycle,
+// or nil otherwise. Orphans are not an error, since the parent of a task
+// may be completed.
+func (t *TaskTree) Validate() error {
+	if len(t.treeErr.Cycles) == 0 {
+		return nil
+	}
+
+	treeErr := t.treeErr
+	return &treeErr
+}
+
+// taskOrder returns the Order of the task with id.
+func (t *TaskTree) taskOrder(id string) int {
+	return t.taskByID[id].Order
+}
+
+// tasks returns the tasks for ids.
+func (t *TaskTree) tasks(ids []string) []Task {
+	tasks := make([]Task, 0, len(ids))
+	for _, id := range ids {
+		tasks = append(tasks, t.taskByID[id])
+	}
+	return tasks
+}
+
+// Task returns the task for id and whether it is in the tree.
+func (t *TaskTree) Task(id string) (Task, bool) {
+	task, ok := t.taskByID[id]
+	return task, ok
+}
+
+// Roots returns the top level tasks, including orphans, in order.
+func (t *TaskTree) Roots() []Task {
+	return t.tasks(t.rootTaskIDs)
+}
+
+// Children returns the direct children of the task with id, in order.
+func (t *TaskTree) Children(id string) []Task {
+	return t.tasks(t.childIDs[id])
+}
+
+// Orphans returns the tasks whose parent is missing or completed.
+func (t *TaskTree) Orphans() []Task {
+	return t.tasks(t.orphanIDs)
+}
+
+// IsOrphan reports whether the task with id has a missing or completed parent.
+func (t *TaskTree) IsOrphan(id string) bool {
+	return t.isOrphan[id]
+}
+
+// Ancestors returns the ancestors of the task with id, starting with its
+// parent and ending with its top level task. Ancestors stop at an orphan
+// and at the task where a parent cycle is broken.
+func (t *TaskTree) Ancestors(id string) []Task {
+	var ancestors []Task
+
+	// parent cycles are already broken, so this ends at a top level task
+	for {
+		parentID, ok := t.parentIDs[id]
+		if !ok {
+			break
+		}
+
+		id = parentID
+		ancestors = append(ancestors, t.taskByID[id])
+	}
+
+	return ancestors
+}
+
+// Depth returns the depth of the task with id, where top level tasks and
+// orphans have a depth of zero. Depth returns -1 if id is not in the tree.
+func (t *TaskTree) Depth(id string) int {
+	if _, ok := t.taskByID[id]; !ok {
+		return -1
+	}
+	return len(t.Ancestors(id))
+}
+
+// Descendants returns all tasks below the task with id in depth-first order.
+func (t *TaskTree) Descendants(id string) []Task {
+	var descendants []Task
+
+	seen := map[string]bool{id: true}
+	var walk func(id string)
+	walk = func(id string) {
+		for _, childID := range t.childIDs[id] {
+			if seen[childID] {
+				continue
+			}
+			seen[childID] = true
+			descendants = append(descendants, t.taskByID[childID])
+			walk(childID)
+		}
+	}
+	walk(id)
+
+	return descendants
+}
+
+// Walk visits every task reachable from the top level tasks in depth-first
+// order, calling fn for each task. See TaskWalkFunc for how fn controls
+// the walk.
+func (t *TaskTree) Walk(fn TaskWalkFunc) error {
+	seen := make(map[string]bool, len(t.taskByID))
+
+	var walk func(ids []string, depth int) error
+	walk = func(ids []string, depth int) error {
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			err := fn(t.taskByID[id], depth)
+			if err == SkipChildren {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			err = walk(t.childIDs[id], depth+1)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return walk(t.rootTaskIDs, 0)
+}
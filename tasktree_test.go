@@ -0,0 +1,138 @@
+package tdapi
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// taskIDs returns the IDs of tasks.
+func taskIDs(tasks []Task) []string {
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func testTasks() []Task {
+	return []Task{
+		{ID: "b", Order: 2},
+		{ID: "a", Order: 1},
+		{ID: "a2", ParentID: "a", Order: 2},
+		{ID: "a1", ParentID: "a", Order: 1},
+		{ID: "a1x", ParentID: "a1", Order: 1},
+		{ID: "done", Order: 3, IsCompleted: true},
+		{ID: "o1", ParentID: "missing", Order: 5},
+		{ID: "o2", ParentID: "done", Order: 4},
+	}
+}
+
+func TestTaskParentIDDecode(t *testing.T) {
+	var task Task
+	err := json.Unmarshal([]byte(`{"id":"2","parent_id":"1"}`), &task)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if task.ParentID != "1" {
+		t.Errorf("ParentID = %q, want %q", task.ParentID, "1")
+	}
+}
+
+func TestTaskTree(t *testing.T) {
+	tree := NewTaskTree(testTasks())
+
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"Roots", taskIDs(tree.Roots()), []string{"a", "b", "done", "o2", "o1"}},
+		{"Children", taskIDs(tree.Children("a")), []string{"a1", "a2"}},
+		{"Orphans", taskIDs(tree.Orphans()), []string{"o2", "o1"}},
+		{"Ancestors", taskIDs(tree.Ancestors("a1x")), []string{"a1", "a"}},
+		{"AncestorsOrphan", taskIDs(tree.Ancestors("o1")), []string{}},
+		{"Descendants", taskIDs(tree.Descendants("a")), []string{"a1", "a1x", "a2"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.got, tc.want) {
+				t.Errorf("Got: %v\nWant: %v", tc.got, tc.want)
+			}
+		})
+	}
+
+	depths := map[string]int{"a": 0, "a1": 1, "a1x": 2, "o2": 0, "none": -1}
+	for id, want := range depths {
+		if got := tree.Depth(id); got != want {
+			t.Errorf("Depth(%q) = %d, want %d", id, got, want)
+		}
+	}
+}
+
+func TestTaskTreeWalk(t *testing.T) {
+	tree := NewTaskTree(testTasks())
+
+	var visited []string
+	err := tree.Walk(func(task Task, depth int) error {
+		visited = append(visited, task.ID)
+		if task.ID == "a1" {
+			return SkipChildren
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{"a", "a1", "a2", "b", "done", "o2", "o1"}
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("Walk order.\n Got: %v\nWant: %v", visited, want)
+	}
+}
+
+func TestTaskTreeCycles(t *testing.T) {
+	tasks := []Task{
+		{ID: "a", ParentID: "c", Order: 1},
+		{ID: "b", ParentID: "a", Order: 1},
+		{ID: "c", ParentID: "b", Order: 1},
+		{ID: "d", ParentID: "d", Order: 2},
+		{ID: "e", Order: 3},
+	}
+
+	tree := NewTaskTree(tasks)
+
+	var treeErr *TaskTreeError
+	if !errors.As(tree.Validate(), &treeErr) {
+		t.Fatalf("Validate() = %v, want *TaskTreeError", tree.Validate())
+	}
+	if want := [][]string{{"a", "c", "b"}, {"d"}}; !reflect.DeepEqual(treeErr.Cycles, want) {
+		t.Errorf("Cycles = %v, want %v", treeErr.Cycles, want)
+	}
+
+	if got, want := taskIDs(tree.Roots()), []string{"a", "d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Roots() = %v, want %v", got, want)
+	}
+	if got := tree.Orphans(); len(got) != 0 {
+		t.Errorf("Orphans() = %v, want none", taskIDs(got))
+	}
+	if got, want := taskIDs(tree.Ancestors("c")), []string{"b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ancestors() = %v, want %v", got, want)
+	}
+
+	var walked []string
+	tree.Walk(func(task Task, depth int) error {
+		walked = append(walked, task.ID)
+		return nil
+	})
+	if want := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(walked, want) {
+		t.Errorf("Walk() visited %v, want %v", walked, want)
+	}
+
+	if err := NewTaskTree(testTasks()).Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
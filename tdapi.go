@@ -30,6 +30,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"golang.org/x/oauth2"
@@ -67,29 +68,12 @@ func (c *TodoistClient) Get(urlString string, query url.Values) (body []byte, er
 
 	fmt.Println("DEBUG:", url.String())
 
-	// execute the request
-	resp, err := c.httpClient.Get(url.String())
-	if err != nil {
-		return body, err
-	}
-	defer resp.Body.Close()
-
-	// read the body
-	body, err = ioutil.ReadAll(resp.Body)
+	req, err := http.NewRequest(http.MethodGet, url.String(), nil)
 	if err != nil {
 		return body, err
 	}
 
-	// check if an error occured and return a APIErrorResponse
-	if codeIsError(resp.StatusCode) {
-		resError := APIErrorResponse{}
-
-		resError.Err = string(body)
-
-		return nil, &resError
-	}
-
-	return body, err
+	return c.do(req)
 }
 
 // Put executes the Todist REST API call, returning the response body.
@@ -116,10 +100,73 @@ func (c *TodoistClient) Put(urlString string, query url.Values, data io.Reader)
 		return body, err
 	}
 
+	return c.do(req)
+}
+
+// Post executes the Todoist REST API call with the JSON encoded data,
+// returning the response body.
+//
+// See https://developer.todoist.com/rest/v2/#overview
+func (c *TodoistClient) Post(urlString string, query url.Values, data io.Reader) (body []byte, err error) {
+	// parse the URL string
+	url, err := url.Parse(apiBase + urlString)
+	if err != nil {
+		return body, err
+	}
+
+	// add the query parameters to the URL
+	url.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodPost, url.String(), data)
+	if err != nil {
+		return body, err
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.do(req)
+}
+
+// Delete executes the Todoist REST API call to delete an object, returning
+// the response body.
+//
+// See https://developer.todoist.com/rest/v2/#overview
+func (c *TodoistClient) Delete(urlString string, query url.Values) (body []byte, err error) {
+	// parse the URL string
+	url, err := url.Parse(apiBase + urlString)
+	if err != nil {
+		return body, err
+	}
+
+	// add the query parameters to the URL
+	url.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodDelete, url.String(), nil)
+	if err != nil {
+		return body, err
+	}
+
+	return c.do(req)
+}
+
+// postJSON executes the Todoist REST API call with v encoded as JSON,
+// returning the response body.
+func (c *TodoistClient) postJSON(urlString string, v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Post(urlString, nil, bytes.NewReader(data))
+}
+
+// do executes the request, returning the response body.
+// An APIErrorResponse is returned if the response status is an error.
+func (c *TodoistClient) do(req *http.Request) (body []byte, err error) {
 	// execute the request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		// handle error
 		return body, err
 	}
 	defer resp.Body.Close()
@@ -132,17 +179,13 @@ func (c *TodoistClient) Put(urlString string, query url.Values, data io.Reader)
 
 	// check if an error occured and return a APIErrorResponse
 	if codeIsError(resp.StatusCode) {
-		resError := APIErrorResponse{}
-
-		err = json.Unmarshal(body, &resError)
-		if err != nil {
-			return nil, err
+		return nil, &APIErrorResponse{
+			Err:        string(body),
+			StatusCode: resp.StatusCode,
 		}
-
-		return nil, &resError
 	}
 
-	return body, err
+	return body, nil
 }
 
 // TodoistClient is a client connection to the Todoist REST API. See https://developer.todoist.com/rest/v2/#overview
@@ -332,3 +375,27 @@ func WriteContentToFile(content []byte, fileName string) (err error) {
 
 	return nil
 }
+
+// writeFileAtomic writes content to a temporary file in the directory of
+// fileName and renames it to fileName, so a failed write never leaves a
+// partial file in its place.
+func writeFileAtomic(content []byte, fileName string) (err error) {
+	file, err := ioutil.TempFile(filepath.Dir(fileName), "."+filepath.Base(fileName)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(content)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), fileName)
+}
@@ -0,0 +1,110 @@
+package tdapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// serverTransport is an http.RoundTripper that sends every request to a
+// local stand-in server, keeping the original path and query. The original
+// host is sent in the X-Forwarded-Host header.
+type serverTransport struct {
+	serverURL *url.URL
+}
+
+// RoundTrip sends the request to the stand-in server.
+func (s serverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Forwarded-Host", req.URL.Host)
+	req.URL.Scheme = s.serverURL.Scheme
+	req.URL.Host = s.serverURL.Host
+	req.Host = ""
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newServerClient returns a TodoistClient that sends requests to a local
+// stand-in server using handler. The server is closed when the test ends.
+func newServerClient(t *testing.T, handler http.Handler) *TodoistClient {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return serverClient(t, server)
+}
+
+// serverClient returns a TodoistClient that sends requests to server.
+func serverClient(t *testing.T, server *httptest.Server) *TodoistClient {
+	t.Helper()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse server URL: %v", err)
+	}
+
+	return &TodoistClient{
+		httpClient: &http.Client{Transport: serverTransport{serverURL}},
+	}
+}
+
+// requestHandler returns a handler that checks the request method, path,
+// and JSON body, then responds with status and body.
+func requestHandler(t *testing.T, method, path string, wantBody map[string]interface{}, status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method || r.URL.Path != path {
+			t.Errorf("Unexpected request.\n Got: %s %s\nWant: %s %s", r.Method, r.URL.Path, method, path)
+		}
+
+		data, _ := io.ReadAll(r.Body)
+		var got map[string]interface{}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Errorf("Cannot decode request: %v", err)
+			}
+		}
+		if !reflect.DeepEqual(got, wantBody) {
+			t.Errorf("Request body does not match.\n Got: %v\nWant: %v", got, wantBody)
+		}
+
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}
+}
+
+func TestRequestErrorStatusCode(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Task not found", http.StatusNotFound)
+	}))
+
+	requests := []struct {
+		name string
+		fn   func() ([]byte, error)
+	}{
+		{"Get", func() ([]byte, error) { return api.Get("/tasks/1", nil) }},
+		{"Put", func() ([]byte, error) { return api.Put("/tasks/1", nil, strings.NewReader("{}")) }},
+		{"Post", func() ([]byte, error) { return api.Post("/tasks/1", nil, strings.NewReader("{}")) }},
+		{"Delete", func() ([]byte, error) { return api.Delete("/tasks/1", nil) }},
+	}
+
+	for _, tc := range requests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.fn()
+
+			var apiErr *APIErrorResponse
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Error = %v, want *APIErrorResponse", err)
+			}
+			if apiErr.StatusCode != http.StatusNotFound || apiErr.Err != "Task not found\n" {
+				t.Errorf("Error = %+v, want status 404 and the response body", apiErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Template row types.
+const (
+	TemplateTask    = "task"
+	TemplateSection = "section"
+	TemplateNote    = "note"
+	TemplateMeta    = "meta"
+)
+
+// TemplateColumns are the columns of the template CSV format, in the order
+// written by WriteTemplate.
+var TemplateColumns = []string{
+	"TYPE", "CONTENT", "DESCRIPTION", "PRIORITY", "INDENT", "AUTHOR",
+	"RESPONSIBLE", "DATE", "DATE_LANG", "TIMEZONE", "DURATION",
+	"DURATION_UNIT",
+}
+
+// A TemplateRow is a row of a project template.
+// See https://todoist.com/help/articles/format-a-csv-file-to-import-into-todoist
+type TemplateRow struct {
+	// Type is one of TemplateTask, TemplateSection, TemplateNote, or
+	// TemplateMeta.
+	Type string
+
+	Content     string
+	Description string
+
+	// Priority is the priority as shown in the app, from 1 for the
+	// highest to 4 for the lowest, which is the reverse of Task.Priority.
+	Priority int
+
+	// Indent is the level of a task, from 1 for a top-level task.
+	Indent int
+
+	Author      string
+	Responsible string
+
+	// Date is the due date in natural language, such as "every monday",
+	// interpreted using DateLang.
+	Date     string
+	DateLang string
+	Timezone string
+
+	Duration     int
+	DurationUnit DurationUnit
+}
+
+// record returns the row as a CSV record in the order of TemplateColumns.
+func (r TemplateRow) record() []string {
+	itoa := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+
+	return []string{
+		r.Type, r.Content, r.Description, itoa(r.Priority), itoa(r.Indent),
+		r.Author, r.Responsible, r.Date, r.DateLang, r.Timezone,
+		itoa(r.Duration), string(r.DurationUnit),
+	}
+}
+
+// ParseTemplate parses a project template in CSV format. Empty rows are
+// skipped and columns that are not in TemplateColumns are ignored.
+func ParseTemplate(r io.Reader) ([]TemplateRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("empty template")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	column := make(map[string]int)
+	for n, name := range header {
+		// an export may start with a byte order mark
+		name = strings.TrimPrefix(name, "\ufeff")
+		column[strings.ToUpper(strings.TrimSpace(name))] = n
+	}
+	if _, ok := column["TYPE"]; !ok {
+		return nil, fmt.Errorf("template has no TYPE column")
+	}
+
+	var rows []TemplateRow
+	for i := 2; ; i++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		field := func(name string) string {
+			n, ok := column[name]
+			if !ok || n >= len(record) {
+				return ""
+			}
+			return record[n]
+		}
+
+		row := TemplateRow{
+			Type:         field("TYPE"),
+			Content:      field("CONTENT"),
+			Description:  field("DESCRIPTION"),
+			Author:       field("AUTHOR"),
+			Responsible:  field("RESPONSIBLE"),
+			Date:         field("DATE"),
+			DateLang:     field("DATE_LANG"),
+			Timezone:     field("TIMEZONE"),
+			DurationUnit: DurationUnit(field("DURATION_UNIT")),
+		}
+
+		numbers := []struct {
+			name string
+			n    *int
+		}{
+			{"PRIORITY", &row.Priority},
+			{"INDENT", &row.Indent},
+			{"DURATION", &row.Duration},
+		}
+		for _, number := range numbers {
+			s := strings.TrimSpace(field(number.name))
+			if s == "" {
+				continue
+			}
+			*number.n, err = strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("record %d: invalid %s %q", i, number.name, s)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// WriteTemplate writes rows as a project template in CSV format, with a
+// header of TemplateColumns.
+func WriteTemplate(w io.Writer, rows []TemplateRow) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write(TemplateColumns)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		err = cw.Write(row.record())
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// ExportProjectAsTemplate returns the project for the given project_id as
+// a template in CSV format.
+// See https://developer.todoist.com/sync/v9/#export-as-a-template-file
+func (c *TodoistClient) ExportProjectAsTemplate(ctx context.Context, project_id string) ([]byte, error) {
+	if project_id == "" {
+		return nil, fmt.Errorf("empty project ID")
+	}
+
+	form := url.Values{}
+	form.Set("project_id", project_id)
+
+	return c.SyncPost(ctx, "/templates/export_as_file", form)
+}
+
+// ImportTemplateIntoProject adds the tasks, sections and comments of the
+// template in CSV format to the project for the given project_id.
+// See https://developer.todoist.com/sync/v9/#import-into-an-existing-project
+func (c *TodoistClient) ImportTemplateIntoProject(ctx context.Context, project_id string, template []byte) error {
+	if project_id == "" {
+		return fmt.Errorf("empty project ID")
+	}
+
+	var buffer bytes.Buffer
+	mw := multipart.NewWriter(&buffer)
+
+	err := mw.WriteField("project_id", project_id)
+	if err != nil {
+		return err
+	}
+
+	part, err := mw.CreateFormFile("file", "template.csv")
+	if err != nil {
+		return err
+	}
+
+	_, err = part.Write(template)
+	if err != nil {
+		return err
+	}
+
+	err = mw.Close()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		syncBase+"/templates/import_into_project", &buffer)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	_, err = c.do(req)
+
+	return err
+}
@@ -0,0 +1,149 @@
+package tdapi
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const exportedTemplate = "\ufeffTYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE,DURATION,DURATION_UNIT,DEADLINE\r\n" +
+	"section,Week 1,,,,,,,,,,,\r\n" +
+	",,,,,,,,,,,,\r\n" +
+	"task,Set up laptop,\"Ask IT for \"\"admin\"\" access\",1,1,Ann (1855589),,today,en,America/New_York,30,minute,\r\n" +
+	"task,Install tools,,4,2,Ann (1855589),Bob (1855590),,,,,,2024-02-01\r\n" +
+	"note,See the wiki,,,,Ann (1855589),,,,,,,\r\n"
+
+func TestParseTemplate(t *testing.T) {
+	rows, err := ParseTemplate(strings.NewReader(exportedTemplate))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []TemplateRow{
+		{Type: TemplateSection, Content: "Week 1"},
+		{
+			Type: TemplateTask, Content: "Set up laptop",
+			Description: `Ask IT for "admin" access`, Priority: 1, Indent: 1,
+			Author: "Ann (1855589)", Date: "today", DateLang: "en",
+			Timezone: "America/New_York", Duration: 30, DurationUnit: DurationMinute,
+		},
+		{
+			Type: TemplateTask, Content: "Install tools", Priority: 4, Indent: 2,
+			Author: "Ann (1855589)", Responsible: "Bob (1855590)",
+		},
+		{Type: TemplateNote, Content: "See the wiki", Author: "Ann (1855589)"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("ParseTemplate() =\n%+v\nwant\n%+v", rows, want)
+	}
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{"empty", ""},
+		{"no type column", "CONTENT\nTask\n"},
+		{"invalid priority", "TYPE,CONTENT,PRIORITY\ntask,Task,high\n"},
+		{"invalid csv", "TYPE,CONTENT\ntask,\"unterminated\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseTemplate(strings.NewReader(tc.csv)); err == nil {
+				t.Errorf("Expected error")
+			}
+		})
+	}
+}
+
+func TestWriteTemplateRoundTrip(t *testing.T) {
+	rows, err := ParseTemplate(strings.NewReader(exportedTemplate))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteTemplate(&buffer, rows); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := "TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE,DURATION,DURATION_UNIT\n" +
+		"section,Week 1,,,,,,,,,,\n" +
+		"task,Set up laptop,\"Ask IT for \"\"admin\"\" access\",1,1,Ann (1855589),,today,en,America/New_York,30,minute\n" +
+		"task,Install tools,,4,2,Ann (1855589),Bob (1855590),,,,,\n" +
+		"note,See the wiki,,,,Ann (1855589),,,,,,\n"
+	if got := buffer.String(); got != want {
+		t.Errorf("WriteTemplate() =\n%s\nwant\n%s", got, want)
+	}
+
+	again, err := ParseTemplate(&buffer)
+	if err != nil || !reflect.DeepEqual(again, rows) {
+		t.Errorf("Round trip = %+v, %v", again, err)
+	}
+}
+
+func TestExportProjectAsTemplate(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sync/v9/templates/export_as_file" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		if got := r.FormValue("project_id"); got != "2203306141" {
+			t.Errorf("project_id = %q", got)
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Write([]byte(exportedTemplate))
+	}))
+
+	data, err := api.ExportProjectAsTemplate(context.Background(), "2203306141")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(data) != exportedTemplate {
+		t.Errorf("Unexpected template %q", data)
+	}
+
+	if _, err := api.ExportProjectAsTemplate(context.Background(), ""); err == nil {
+		t.Errorf("Expected error for empty project ID")
+	}
+}
+
+func TestImportTemplateIntoProject(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sync/v9/templates/import_into_project" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("Cannot parse form: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if got := r.FormValue("project_id"); got != "2203306141" {
+			t.Errorf("project_id = %q", got)
+		}
+
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("No file: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := ioutil.ReadAll(file)
+		if string(data) != exportedTemplate {
+			t.Errorf("Unexpected file %q", data)
+		}
+
+		w.Write([]byte(`{"status":"ok","tasks":[],"sections":[],"comments":[]}`))
+	}))
+
+	err := api.ImportTemplateIntoProject(context.Background(), "2203306141", []byte(exportedTemplate))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import "sort"
+
+// breakParentCycles finds each cycle in parentIDs, which maps an ID to its
+// parent ID, and breaks it by removing the parent of the lowest ID in the
+// cycle. IDs are visited in the order of ids, and the cycles are returned
+// in the order they are found.
+func breakParentCycles(ids []string, parentIDs map[string]string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(ids))
+
+	var cycles [][]string
+	for _, id := range ids {
+		var path []string
+
+		hasParent := true
+		for state[id] == unvisited {
+			state[id] = visiting
+			path = append(path, id)
+
+			parentID, ok := parentIDs[id]
+			if !ok {
+				hasParent = false
+				break
+			}
+			id = parentID
+		}
+
+		if hasParent && state[id] == visiting {
+			// the cycle starts at the first occurrence of id in the path
+			var cycle []string
+			for n, pathID := range path {
+				if pathID == id {
+					cycle = path[n:]
+					break
+				}
+			}
+
+			cycles = append(cycles, cycle)
+
+			lowestID := cycle[0]
+			for _, cycleID := range cycle {
+				if cycleID < lowestID {
+					lowestID = cycleID
+				}
+			}
+			delete(parentIDs, lowestID)
+		}
+
+		for _, pathID := range path {
+			state[pathID] = visited
+		}
+	}
+
+	return cycles
+}
+
+// sortByOrder sorts ids by the order returned by orderOf, then by ID.
+func sortByOrder(ids []string, orderOf func(id string) int) {
+	sort.SliceStable(ids, func(i, j int) bool {
+		a, b := orderOf(ids[i]), orderOf(ids[j])
+		if a != b {
+			return a < b
+		}
+		return ids[i] < ids[j]
+	})
+}
@@ -50,48 +50,53 @@ var PriorityToHexColor = [...]string{
 	4: "#d1453b",
 }
 
+// A Comment is a note on a task or project.
+// See https://developer.todoist.com/rest/v2/?shell#comments
 type Comment struct {
 	// Comment id.
-	ID int64 `json:"id"`
+	ID string `json:"id"`
 
-	// CommentÃ¢ÂÂs task id (for task comments).
-	TaskID int64 `json:"task_id,omitempty"`
+	// Comment's task id (for task comments).
+	TaskID string `json:"task_id,omitempty"`
 
-	// CommentÃ¢ÂÂs project id (for project comments).
-	ProjectID int64 `json:"project_id,omitempty"`
+	// Comment's project id (for project comments).
+	ProjectID string `json:"project_id,omitempty"`
 
 	// Date and time when comment was added, RFC3339 format in UTC.
-	Posted time.Time `json:"posted"`
+	PostedAt time.Time `json:"posted_at"`
 
 	// Comment content.
 	Content string `json:"content"`
 
 	// Attachment file (optional).
-	Attachment struct {
-		// The name of the file.
-		FileName string `json:"file_name,omitempty"`
-
-		// MIME type (i.e. text/plain, image/png).
-		FileType string `json:"file_type,omitempty"`
-
-		// The URL where the file is located (a string value
-		// representing an HTTP URL). Note that we donÃ¢ÂÂt cache
-		// the remote content on our servers and stream or expose
-		// files directly from third party resources. In particular
-		// this means that you should avoid providing links to
-		// non-encrypted (plain HTTP) resources, as exposing this
-		// files in Todoist may issue a browser warning.
-		FileURL string `json:"file_url,omitempty"`
-
-		FileSize    int64  `json:"file_size,omitempty"`
-		UploadState string `json:"upload_state,omitempty"`
-
-		ResourceType string `json:"resource_type"`
-
-		SiteName    string `json:"site_name,omitempty"`
-		Description string `json:"description,omitempty"`
-		Title       string `json:"title,omitempty"`
-		URL         string `json:"url,omitempty"`
-		FavIcon     string `json:"favicon,omitempty"`
-	} `json:"attachment"`
+	Attachment *Attachment `json:"attachment,omitempty"`
+}
+
+// An Attachment is a file or link attached to a comment.
+type Attachment struct {
+	// The name of the file.
+	FileName string `json:"file_name,omitempty"`
+
+	// MIME type (i.e. text/plain, image/png).
+	FileType string `json:"file_type,omitempty"`
+
+	// The URL where the file is located (a string value
+	// representing an HTTP URL). Note that we don't cache
+	// the remote content on our servers and stream or expose
+	// files directly from third party resources. In particular
+	// this means that you should avoid providing links to
+	// non-encrypted (plain HTTP) resources, as exposing this
+	// files in Todoist may issue a browser warning.
+	FileURL string `json:"file_url,omitempty"`
+
+	FileSize    int64  `json:"file_size,omitempty"`
+	UploadState string `json:"upload_state,omitempty"`
+
+	ResourceType string `json:"resource_type"`
+
+	SiteName    string `json:"site_name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Title       string `json:"title,omitempty"`
+	URL         string `json:"url,omitempty"`
+	FavIcon     string `json:"favicon,omitempty"`
 }
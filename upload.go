@@ -0,0 +1,194 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+)
+
+// DefaultMaxUploadSize is the default maximum size of an uploaded file,
+// which is the limit for Todoist Pro accounts. Free accounts have a lower
+// limit that is enforced by the server.
+const DefaultMaxUploadSize = 100 << 20
+
+// ErrUploadTooLarge is returned when a file exceeds the maximum upload size.
+var ErrUploadTooLarge = errors.New("upload exceeds maximum size")
+
+// UploadOptions are the optional parameters for an upload.
+type UploadOptions struct {
+	// MaxSize is the maximum size of the file in bytes.
+	// If zero, DefaultMaxUploadSize is used.
+	MaxSize int64
+
+	// Progress, if set, is called as the file is sent with the number
+	// of bytes sent and the total size, which is -1 if unknown.
+	Progress func(sent int64, total int64)
+}
+
+// progressReader is an io.Reader that reports the bytes read.
+type progressReader struct {
+	r        io.Reader
+	sent     int64
+	total    int64
+	progress func(sent int64, total int64)
+}
+
+// Read reads from the underlying reader, reporting progress.
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.sent += int64(n)
+	if n > 0 && p.progress != nil {
+		p.progress(p.sent, p.total)
+	}
+	return n, err
+}
+
+// readerSize returns the size of the data in r, or -1 if unknown.
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case interface{ Stat() (os.FileInfo, error) }:
+		info, err := v.Stat()
+		if err == nil && info.Mode().IsRegular() {
+			return info.Size()
+		}
+	}
+	return -1
+}
+
+// Upload streams the data read from r to Todoist as a file named fileName,
+// returning an Attachment that can be used with CreateComment.
+//
+// The size of r is checked before the upload if it is known, such as for
+// an *os.File or *bytes.Reader, and otherwise as the data is sent.
+// ErrUploadTooLarge is returned if the size exceeds the maximum.
+//
+// See https://developer.todoist.com/sync/v9/#uploads
+func (c *TodoistClient) Upload(ctx context.Context, r io.Reader, fileName string, opts *UploadOptions) (*Attachment, error) {
+	if fileName == "" {
+		return nil, fmt.Errorf("empty file name")
+	}
+
+	var maxSize int64 = DefaultMaxUploadSize
+	var progress func(int64, int64)
+	if opts != nil {
+		if opts.MaxSize > 0 {
+			maxSize = opts.MaxSize
+		}
+		progress = opts.Progress
+	}
+
+	size := readerSize(r)
+	if size > maxSize {
+		return nil, ErrUploadTooLarge
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(fileName))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	mw := multipart.NewWriter(pw)
+
+	// write the multipart body as the request reads it
+	go func() {
+		err := mw.WriteField("file_name", fileName)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(
+			`form-data; name="file"; filename=%q`, fileName))
+		header.Set("Content-Type", contentType)
+
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		// read one byte past the maximum to detect a file that is too large
+		src := &progressReader{
+			r:        io.LimitReader(r, maxSize+1),
+			total:    size,
+			progress: progress,
+		}
+
+		n, err := io.Copy(part, src)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if n > maxSize {
+			pw.CloseWithError(ErrUploadTooLarge)
+			return
+		}
+
+		pw.CloseWithError(mw.Close())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		syncBase+"/uploads/add", pr)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	body, err := c.do(req)
+	if err != nil {
+		if errors.Is(err, ErrUploadTooLarge) {
+			return nil, ErrUploadTooLarge
+		}
+		return nil, err
+	}
+
+	attachment := &Attachment{}
+	err = json.Unmarshal(body, attachment)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// UploadFile uploads the local file at path, returning an Attachment that
+// can be used with CreateComment. See Upload for details.
+func (c *TodoistClient) UploadFile(ctx context.Context, path string, opts *UploadOptions) (*Attachment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return c.Upload(ctx, file, filepath.Base(path), opts)
+}
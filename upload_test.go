@@ -0,0 +1,92 @@
+package tdapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUploadFile(t *testing.T) {
+	const content = "line 1\nline 2\n"
+
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/sync/v9/uploads/add" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("Cannot read file: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+
+		if string(data) != content {
+			t.Errorf("File content = %q, want %q", data, content)
+		}
+		if got := header.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/plain") {
+			t.Errorf("Content-Type = %q, want text/plain", got)
+		}
+		if got := r.FormValue("file_name"); got != "app.txt" {
+			t.Errorf("file_name = %q, want %q", got, "app.txt")
+		}
+
+		io.WriteString(w, `{"file_name":"app.txt","file_size":14,"file_type":"text/plain",
+			"file_url":"https://example.com/app.txt","resource_type":"file","upload_state":"completed"}`)
+	}))
+
+	path := filepath.Join(t.TempDir(), "app.txt")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Cannot write file: %v", err)
+	}
+
+	var lastSent, lastTotal int64
+	attachment, err := api.UploadFile(context.Background(), path, &UploadOptions{
+		Progress: func(sent, total int64) { lastSent, lastTotal = sent, total },
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if lastSent != int64(len(content)) || lastTotal != int64(len(content)) {
+		t.Errorf("Progress = %d/%d, want %d/%d", lastSent, lastTotal, len(content), len(content))
+	}
+
+	want := Attachment{
+		FileName: "app.txt", FileSize: 14, FileType: "text/plain",
+		FileURL: "https://example.com/app.txt", ResourceType: "file", UploadState: "completed",
+	}
+	if *attachment != want {
+		t.Errorf("Attachment does not match.\n Got: %+v\nWant: %+v\n", *attachment, want)
+	}
+}
+
+func TestUploadTooLarge(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		io.WriteString(w, `{}`)
+	}))
+
+	tests := []struct {
+		name string
+		r    io.Reader
+	}{
+		{"known size", strings.NewReader("0123456789")},
+		{"unknown size", io.MultiReader(strings.NewReader("01234"), strings.NewReader("56789"))},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := api.Upload(context.Background(), tc.r, "data.bin", &UploadOptions{MaxSize: 8})
+			if !errors.Is(err, ErrUploadTooLarge) {
+				t.Errorf("Err = %v, want %v", err, ErrUploadTooLarge)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2021 Bill Nixon
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tdapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// A TimezoneInfo describes the timezone of a user.
+type TimezoneInfo struct {
+	Timezone  string `json:"timezone"`
+	GMTString string `json:"gmt_string"`
+	Hours     int    `json:"hours"`
+	Minutes   int    `json:"minutes"`
+	IsDST     int    `json:"is_dst"`
+}
+
+// A User is the Todoist user of the client.
+// See https://developer.todoist.com/sync/v9/#user
+type User struct {
+	ID             string       `json:"id"`
+	Email          string       `json:"email"`
+	FullName       string       `json:"full_name"`
+	InboxProjectID string       `json:"inbox_project_id"`
+	TeamInboxID    string       `json:"team_inbox_id,omitempty"`
+	Lang           string       `json:"lang"`
+	TzInfo         TimezoneInfo `json:"tz_info"`
+
+	// StartDay is the first day of the week, 1 for Monday to 7 for Sunday.
+	StartDay int `json:"start_day"`
+
+	// NextWeek is the day for "next week", 1 for Monday to 7 for Sunday.
+	NextWeek int `json:"next_week"`
+
+	DateFormat     int     `json:"date_format"`
+	TimeFormat     int     `json:"time_format"`
+	Karma          float64 `json:"karma"`
+	KarmaTrend     string  `json:"karma_trend"`
+	DailyGoal      int     `json:"daily_goal"`
+	WeeklyGoal     int     `json:"weekly_goal"`
+	DaysOff        []int   `json:"days_off"`
+	CompletedCount int     `json:"completed_count"`
+	CompletedToday int     `json:"completed_today"`
+	IsPremium      bool    `json:"is_premium"`
+	PremiumStatus  string  `json:"premium_status"`
+	JoinedAt       string  `json:"joined_at"`
+}
+
+// Location returns the timezone of the user. If the timezone is not known
+// to the system, a fixed zone with the offset of the user is returned.
+func (u User) Location() *time.Location {
+	if u.TzInfo.Timezone != "" {
+		loc, err := time.LoadLocation(u.TzInfo.Timezone)
+		if err == nil {
+			return loc
+		}
+	}
+
+	offset := u.TzInfo.Hours*60*60 + u.TzInfo.Minutes*60
+	if u.TzInfo.Hours < 0 {
+		offset = u.TzInfo.Hours*60*60 - u.TzInfo.Minutes*60
+	}
+
+	name := u.TzInfo.GMTString
+	if name == "" {
+		name = "UTC"
+	}
+
+	return time.FixedZone(name, offset)
+}
+
+// WeekStart returns the first day of the week for the user, which is
+// Monday if StartDay is not set.
+func (u User) WeekStart() time.Weekday {
+	if u.StartDay < 1 || u.StartDay > 7 {
+		return time.Monday
+	}
+
+	return time.Weekday(u.StartDay % 7)
+}
+
+// Now returns the current time in the timezone of the user.
+func (u User) Now() time.Time {
+	return time.Now().In(u.Location())
+}
+
+// UserSettings are the notification settings of the user.
+// See https://developer.todoist.com/sync/v9/#user-settings
+type UserSettings struct {
+	ReminderPush          bool `json:"reminder_push"`
+	ReminderDesktop       bool `json:"reminder_desktop"`
+	ReminderEmail         bool `json:"reminder_email"`
+	CompletedSoundDesktop bool `json:"completed_sound_desktop"`
+	CompletedSoundMobile  bool `json:"completed_sound_mobile"`
+}
+
+// GetUser returns the user of the client.
+func (c *TodoistClient) GetUser(ctx context.Context) (User, error) {
+	response, err := c.Sync(ctx, "", []string{ResourceUser})
+	if err != nil {
+		return User{}, err
+	}
+
+	if response.User == nil {
+		return User{}, fmt.Errorf("no user in response")
+	}
+
+	return *response.User, nil
+}
+
+// GetUserSettings returns the settings of the user of the client.
+func (c *TodoistClient) GetUserSettings(ctx context.Context) (UserSettings, error) {
+	response, err := c.Sync(ctx, "", []string{ResourceUserSettings})
+	if err != nil {
+		return UserSettings{}, err
+	}
+
+	if response.UserSettings == nil {
+		return UserSettings{}, fmt.Errorf("no user settings in response")
+	}
+
+	return *response.UserSettings, nil
+}
+
+// A CompletedItemCount is the number of completed tasks in a project.
+type CompletedItemCount struct {
+	ID        string `json:"id"`
+	Completed int    `json:"completed"`
+}
+
+// A DayStats is the number of tasks completed on a day.
+type DayStats struct {
+	Date           string               `json:"date"`
+	TotalCompleted int                  `json:"total_completed"`
+	Items          []CompletedItemCount `json:"items"`
+}
+
+// A WeekStats is the number of tasks completed in a week.
+type WeekStats struct {
+	From           string               `json:"from"`
+	To             string               `json:"to"`
+	TotalCompleted int                  `json:"total_completed"`
+	Items          []CompletedItemCount `json:"items"`
+}
+
+// A Streak is a run of days or weeks where the goal was met.
+type Streak struct {
+	Count int    `json:"count"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Goals are the productivity goals of the user.
+type Goals struct {
+	DailyGoal           int    `json:"daily_goal"`
+	WeeklyGoal          int    `json:"weekly_goal"`
+	CurrentDailyStreak  Streak `json:"current_daily_streak"`
+	CurrentWeeklyStreak Streak `json:"current_weekly_streak"`
+	MaxDailyStreak      Streak `json:"max_daily_streak"`
+	MaxWeeklyStreak     Streak `json:"max_weekly_streak"`
+	IgnoreDays          []int  `json:"ignore_days"`
+	VacationMode        int    `json:"vacation_mode"`
+	KarmaDisabled       int    `json:"karma_disabled"`
+}
+
+// ProductivityStats are the productivity statistics of the user.
+// See https://developer.todoist.com/sync/v9/#get-productivity-stats
+type ProductivityStats struct {
+	Karma           float64     `json:"karma"`
+	KarmaTrend      string      `json:"karma_trend"`
+	KarmaLastUpdate float64     `json:"karma_last_update"`
+	CompletedCount  int         `json:"completed_count"`
+	DaysItems       []DayStats  `json:"days_items"`
+	WeekItems       []WeekStats `json:"week_items"`
+	Goals           Goals       `json:"goals"`
+}
+
+// GetProductivityStats returns the productivity statistics of the user.
+func (c *TodoistClient) GetProductivityStats(ctx context.Context) (response ProductivityStats, err error) {
+	body, err := c.SyncPost(ctx, "/completed/get_stats", url.Values{})
+	if err != nil {
+		return response, err
+	}
+
+	err = json.Unmarshal(body, &response)
+
+	return response, err
+}
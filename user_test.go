@@ -0,0 +1,166 @@
+package tdapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestUserLocation(t *testing.T) {
+	tests := []struct {
+		name   string
+		tz     TimezoneInfo
+		offset int
+	}{
+		{"known", TimezoneInfo{Timezone: "Asia/Kolkata", Hours: 5, Minutes: 30}, 5*3600 + 30*60},
+		{"unknown positive", TimezoneInfo{Timezone: "Nowhere/Town", GMTString: "+05:45", Hours: 5, Minutes: 45}, 5*3600 + 45*60},
+		{"unknown negative", TimezoneInfo{Timezone: "Nowhere/Town", GMTString: "-03:30", Hours: -3, Minutes: 30}, -(3*3600 + 30*60)},
+		{"empty", TimezoneInfo{}, 0},
+	}
+
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			u := User{TzInfo: tc.tz}
+			if _, offset := now.In(u.Location()).Zone(); offset != tc.offset {
+				t.Errorf("offset = %d, want %d", offset, tc.offset)
+			}
+		})
+	}
+}
+
+func TestUserWeekStart(t *testing.T) {
+	tests := []struct {
+		startDay int
+		want     time.Weekday
+	}{
+		{0, time.Monday},
+		{1, time.Monday},
+		{6, time.Saturday},
+		{7, time.Sunday},
+		{8, time.Monday},
+	}
+
+	for _, tc := range tests {
+		if got := (User{StartDay: tc.startDay}).WeekStart(); got != tc.want {
+			t.Errorf("WeekStart() with StartDay %d = %v, want %v", tc.startDay, got, tc.want)
+		}
+	}
+}
+
+func TestDayBucketForUser(t *testing.T) {
+	// Friday 23:00 in UTC is Saturday 08:00 in Tokyo
+	now := time.Date(2024, 1, 12, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		user User
+		date string
+		want DueBucket
+	}{
+		{"user today", User{TzInfo: TimezoneInfo{Timezone: "Asia/Tokyo"}}, "2024-01-13", BucketToday},
+		{"user yesterday", User{TzInfo: TimezoneInfo{Timezone: "Asia/Tokyo"}}, "2024-01-12", BucketOverdue},
+		{"monday start", User{TzInfo: TimezoneInfo{Timezone: "Asia/Tokyo"}, StartDay: 1}, "2024-01-15", BucketLater},
+		{"sunday start", User{TzInfo: TimezoneInfo{Timezone: "Asia/Tokyo"}, StartDay: 7}, "2024-01-15", BucketLater},
+		{"saturday start", User{TzInfo: TimezoneInfo{Timezone: "Asia/Tokyo"}, StartDay: 6}, "2024-01-16", BucketThisWeek},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := (TaskDue{Date: tc.date}).DayBucketFor(now, &tc.user); got != tc.want {
+				t.Errorf("DayBucketFor() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("resource_types") {
+		case `["user"]`:
+			w.Write([]byte(`{"sync_token":"t","full_sync":true,"user":{"id":"1","full_name":"Ann","karma":684.0,"daily_goal":5,"start_day":7,"tz_info":{"timezone":"Europe/Paris"}}}`))
+		case `["user_settings"]`:
+			w.Write([]byte(`{"sync_token":"t","full_sync":true,"user_settings":{"reminder_email":true}}`))
+		default:
+			t.Errorf("Unexpected resource_types %q", r.FormValue("resource_types"))
+		}
+	}))
+	ctx := context.Background()
+
+	user, err := api.GetUser(ctx)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.FullName != "Ann" || user.Karma != 684 || user.DailyGoal != 5 ||
+		user.WeekStart() != time.Sunday || user.Location().String() != "Europe/Paris" {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+
+	settings, err := api.GetUserSettings(ctx)
+	if err != nil {
+		t.Fatalf("GetUserSettings: %v", err)
+	}
+	if settings != (UserSettings{ReminderEmail: true}) {
+		t.Errorf("Unexpected settings: %+v", settings)
+	}
+}
+
+func TestGetProductivityStats(t *testing.T) {
+	api := newServerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sync/v9/completed/get_stats" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"karma": 86394.0,
+			"karma_trend": "up",
+			"completed_count": 41,
+			"days_items": [{"date": "2024-01-12", "total_completed": 3, "items": [{"id": "220", "completed": 3}]}],
+			"week_items": [{"from": "2024-01-08", "to": "2024-01-14", "total_completed": 9, "items": []}],
+			"goals": {"daily_goal": 5, "weekly_goal": 25, "current_daily_streak": {"count": 2, "start": "2024-01-11", "end": "2024-01-12"}, "ignore_days": [6, 7]}
+		}`))
+	}))
+
+	stats, err := api.GetProductivityStats(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if stats.Karma != 86394 || stats.CompletedCount != 41 ||
+		stats.DaysItems[0].Items[0].Completed != 3 ||
+		stats.WeekItems[0].TotalCompleted != 9 ||
+		stats.Goals.WeeklyGoal != 25 || stats.Goals.CurrentDailyStreak.Count != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestDueHelpersForUser(t *testing.T) {
+	// Friday 23:00 in UTC is Saturday 08:00 in Tokyo
+	now := time.Date(2024, 1, 12, 23, 0, 0, 0, time.UTC)
+	tokyo := &User{TzInfo: TimezoneInfo{Timezone: "Asia/Tokyo"}, StartDay: 6}
+
+	due := TaskDue{Date: "2024-01-12"}
+	if due.IsOverdueFor(now, nil) {
+		t.Errorf("IsOverdueFor(nil) = true, want false on the due day in UTC")
+	}
+	if !due.IsOverdueFor(now, tokyo) {
+		t.Errorf("IsOverdueFor(tokyo) = false, want true on the next day in Tokyo")
+	}
+
+	if got := due.DayBucketFor(now, nil); got != BucketToday {
+		t.Errorf("DayBucketFor(nil) = %v, want %v", got, BucketToday)
+	}
+
+	// the week of a user starting on Saturday runs to Friday the 19th
+	deadline := TaskDeadline{Date: "2024-01-16"}
+	if got := deadline.DayBucketFor(now, tokyo); got != BucketThisWeek {
+		t.Errorf("DayBucketFor(tokyo) = %v, want %v", got, BucketThisWeek)
+	}
+	// weeks starting on Monday run to Sunday the 14th
+	if got := deadline.DayBucketFor(now, nil); got != BucketLater {
+		t.Errorf("DayBucketFor(nil) = %v, want %v", got, BucketLater)
+	}
+	if !deadline.IsOverdueFor(now.AddDate(0, 0, 4), tokyo) {
+		t.Errorf("IsOverdueFor(tokyo) = false, want true after the deadline")
+	}
+}